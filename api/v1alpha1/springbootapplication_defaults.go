@@ -0,0 +1,91 @@
+/*
+Copyright 2026 Daniel Taylor.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+// The defaults for each SpringFramework live here so the controller and the webhook use the same values
+
+func createRollout(maxSurge string, maxUnavailable int, minReadySeconds int, progressDeadlineSeconds int) RolloutConfig {
+	return RolloutConfig{
+		MaxSurge:                ptr.To(intstr.FromString(maxSurge)),
+		MaxUnavailable:          ptr.To(intstr.FromInt(maxUnavailable)),
+		MinReadySeconds:         ptr.To(int32(minReadySeconds)),
+		ProgressDeadlineSeconds: ptr.To(int32(progressDeadlineSeconds)),
+	}
+}
+
+// DefaultRollout returns the rolling update settings for the framework. Replicas are only taken down once
+// their replacements are ready. Slower starting frameworks surge less and get longer before a rollout
+// counts as failed, while native images are quick enough to surge the whole deployment at once.
+func DefaultRollout(springFramework SpringFramework) (RolloutConfig, error) {
+	switch springFramework {
+	case SpringWeb:
+		return createRollout("25%", 0, 10, 600), nil
+	case SpringWebflux:
+		return createRollout("50%", 0, 5, 300), nil
+	case SpringNative:
+		return createRollout("100%", 0, 0, 120), nil
+	}
+
+	return RolloutConfig{}, fmt.Errorf("unhandled spring framework: %s", springFramework)
+}
+
+func createProbeTiming(periodSeconds int, timeoutSeconds int, failureThreshold int) *ProbeConfig {
+	return &ProbeConfig{
+		PeriodSeconds:    ptr.To(int32(periodSeconds)),
+		TimeoutSeconds:   ptr.To(int32(timeoutSeconds)),
+		FailureThreshold: ptr.To(int32(failureThreshold)),
+	}
+}
+
+// DefaultProbes returns the probe timings for the framework, and how long it usually takes to start. The
+// startup probe allows 5 minutes for web apps, which can be slow to start, while native images start in well
+// under a second so are checked often and given far less time. Readiness is checked more often for the
+// quicker frameworks so new pods join the service sooner.
+func DefaultProbes(springFramework SpringFramework) (ProbesConfig, error) {
+	switch springFramework {
+	case SpringWeb:
+		return ProbesConfig{
+			ExpectedStartupSeconds: ptr.To[int32](60),
+			Liveness:               createProbeTiming(10, 1, 3),
+			Readiness:              createProbeTiming(10, 1, 3),
+			Startup:                createProbeTiming(10, 1, 30),
+		}, nil
+	case SpringWebflux:
+		return ProbesConfig{
+			ExpectedStartupSeconds: ptr.To[int32](30),
+			Liveness:               createProbeTiming(10, 1, 3),
+			Readiness:              createProbeTiming(5, 1, 3),
+			Startup:                createProbeTiming(5, 1, 36),
+		}, nil
+	case SpringNative:
+		return ProbesConfig{
+			ExpectedStartupSeconds: ptr.To[int32](5),
+			Liveness:               createProbeTiming(10, 1, 3),
+			Readiness:              createProbeTiming(5, 1, 3),
+			Startup:                createProbeTiming(1, 1, 30),
+		}, nil
+	}
+
+	return ProbesConfig{}, fmt.Errorf("unhandled spring framework: %s", springFramework)
+}
@@ -18,8 +18,11 @@ package v1alpha1
 
 import (
 	scalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 type ResourcePreset string
@@ -50,11 +53,98 @@ type UtilizationTarget struct {
 
 	// CPU percentage target
 	CpuPercentage *int32 `json:"cpuPercentage,omitempty"`
+
+	// Scale on memory usage as well as CPU, using the default target for the application type
+	Memory bool `json:"memory,omitempty"`
+
+	// Memory percentage target. Setting this also turns on scaling on memory usage
+	MemoryPercentage *int32 `json:"memoryPercentage,omitempty"`
+}
+
+type AutoscalerEngine string
+
+const (
+	EngineHPA  AutoscalerEngine = "hpa"
+	EngineKeda AutoscalerEngine = "keda"
+)
+
+// KEDA trigger, see https://keda.sh/docs/latest/scalers/ for the available types and their metadata
+type KedaTrigger struct {
+	// +kubebuilder:validation:MinLength=1
+	// Scaler type, for example kafka, rabbitmq or cron
+	Type string `json:"type"`
+
+	// Name of the trigger
+	Name string `json:"name,omitempty"`
+
+	// Scaler specific settings
+	Metadata map[string]string `json:"metadata"`
+
+	// Name of a TriggerAuthentication in the application's namespace holding the scaler's credentials
+	AuthenticationRef string `json:"authenticationRef,omitempty"`
+
+	// +kubebuilder:validation:Enum=AverageValue;Value;Utilization
+	// How the trigger's metric is compared against its target
+	MetricType scalingv2.MetricTargetType `json:"metricType,omitempty"`
+}
+
+// Event driven autoscaling settings using KEDA
+type KedaConfig struct {
+	// +kubebuilder:validation:MinItems=1
+	// Triggers to scale on
+	Triggers []KedaTrigger `json:"triggers"`
+
+	// +kubebuilder:validation:Minimum=1
+	// Seconds between checks of each trigger
+	PollingInterval *int32 `json:"pollingInterval,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+	// Seconds to wait after the last active trigger before scaling to zero
+	CooldownPeriod *int32 `json:"cooldownPeriod,omitempty"`
+
+	// Scale down to zero replicas while no triggers are active
+	ScaleToZero bool `json:"scaleToZero,omitempty"`
+}
+
+// Time window with its own replica limits, for example to scale up ahead of a daily peak. The window
+// opens at each occurrence of start and closes at the next occurrence of end
+type ScalingSchedule struct {
+	// +kubebuilder:validation:MinLength=1
+	// Name of the schedule, reported in the status while it is active
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:MinLength=1
+	// Cron expression for when the window opens, for example "0 8 * * 1-5"
+	Start string `json:"start"`
+
+	// +kubebuilder:validation:MinLength=1
+	// Cron expression for when the window closes, for example "0 18 * * 1-5"
+	End string `json:"end"`
+
+	// IANA time zone the cron expressions are in, for example Europe/London. Defaults to UTC
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+	// Min replicas while the window is open
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	// Max replicas while the window is open
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
 }
 
 // Custom Autoscaling configuration.
 type AutoscalingConfig struct {
 
+	// +kubebuilder:default=true
+	// Scale the application automatically. When disabled, spec.replicas is used instead
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// +kubebuilder:validation:Enum=hpa;keda
+	// +kubebuilder:default=hpa
+	// Scale with a HorizontalPodAutoscaler or a KEDA ScaledObject
+	Engine AutoscalerEngine `json:"engine,omitempty"`
+
 	// +kubebuilder:default=2
 	// Min replicas
 	MinReplicas int `json:"minReplicas,omitempty"`
@@ -66,8 +156,366 @@ type AutoscalingConfig struct {
 	// Utilization target
 	TargetUtilization UtilizationTarget `json:"utilizationTarget,omitempty"`
 
+	// Additional Pods, Object or External metrics to scale on, for example requests per second
+	// served through a metrics adapter. The autoscaler uses whichever metric needs the most replicas
+	Metrics []scalingv2.MetricSpec `json:"metrics,omitempty"`
+
+	// Windows overriding the min and max replicas. The first open window is used
+	Schedules []ScalingSchedule `json:"schedules,omitempty"`
+
 	// Scaling behaviour
 	Behaviour *scalingv2.HorizontalPodAutoscalerBehavior `json:"behaviour,omitempty"`
+
+	// KEDA settings (keda engine only)
+	Keda *KedaConfig `json:"keda,omitempty"`
+}
+
+type ExposeMode string
+
+const (
+	ExposeIngress ExposeMode = "ingress"
+	ExposeGateway ExposeMode = "gateway"
+)
+
+// Reference to the Gateway an HTTPRoute should attach to
+type GatewayReference struct {
+	// +kubebuilder:validation:MinLength=1
+	// Name of the Gateway
+	Name string `json:"name"`
+
+	// Namespace of the Gateway. Defaults to the namespace of the application
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the listener on the Gateway to attach to
+	SectionName string `json:"sectionName,omitempty"`
+}
+
+// TLS settings for the exposed host
+type ExposeTLS struct {
+	// Name of the secret holding the certificate for the host. Only used by ingresses,
+	// gateways terminate TLS on their own listeners.
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// Describes how the application should be reachable from outside the cluster
+type ExposeConfig struct {
+	// +kubebuilder:validation:Enum=ingress;gateway
+	// +kubebuilder:default=ingress
+	// Whether to create an Ingress or a Gateway API HTTPRoute
+	Mode ExposeMode `json:"mode,omitempty"`
+
+	// +kubebuilder:validation:MinLength=1
+	// Host name the application is served on
+	Host string `json:"host"`
+
+	// TLS settings. When set, the application is served over https
+	TLS *ExposeTLS `json:"tls,omitempty"`
+
+	// Ingress class to use (ingress mode only)
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+
+	// Gateway to attach the route to (required in gateway mode)
+	ParentRef *GatewayReference `json:"parentRef,omitempty"`
+}
+
+// Secret holding Spring configuration (for example credentials) which is loaded as an additional config file
+type SecretConfigSource struct {
+	// +kubebuilder:validation:MinLength=1
+	// Name of the secret
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Pattern=`^.+\.(yaml|yml|properties)$`
+	// +kubebuilder:default=application.yaml
+	// Key within the secret holding the configuration file
+	Key string `json:"key,omitempty"`
+}
+
+// Shared configuration in a ConfigMap or Secret, loaded as an additional config file. Exactly one of
+// configMap and secret must be set
+type ConfigFromSource struct {
+	// Name of the ConfigMap
+	ConfigMap string `json:"configMap,omitempty"`
+
+	// Name of the secret
+	Secret string `json:"secret,omitempty"`
+
+	// +kubebuilder:validation:Pattern=`^.+\.(yaml|yml|properties)$`
+	// +kubebuilder:default=application.yaml
+	// Key holding the configuration file
+	Key string `json:"key,omitempty"`
+
+	// Start the application even if the ConfigMap, Secret or key doesn't exist
+	Optional bool `json:"optional,omitempty"`
+}
+
+type RolloutStrategy string
+
+const (
+	RollingUpdate RolloutStrategy = "rollingUpdate"
+	Canary        RolloutStrategy = "canary"
+	BlueGreen     RolloutStrategy = "blueGreen"
+)
+
+// Canary rollout settings
+type CanaryConfig struct {
+	// Percentage of traffic sent to the new version at each step, for example [10, 25, 50]. Traffic is
+	// shifted using the ratio of canary to stable replicas, so it is approximate. The canary never runs
+	// more replicas than the stable version, so steps over 50 get an even split. Defaults to [10, 50]
+	Steps []int32 `json:"steps,omitempty"`
+
+	// +kubebuilder:validation:Pattern=`^([0-9]+(ms|s|m|h))+$`
+	// +kubebuilder:default="5m"
+	// How long each step runs before it is analysed
+	StepDuration string `json:"stepDuration,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=5
+	// Max percentage of requests to the canary which can fail with a server error before it is aborted
+	MaxErrorPercentage int32 `json:"maxErrorPercentage,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=3
+	// Container restarts of a canary pod which abort the rollout
+	MaxRestarts int32 `json:"maxRestarts,omitempty"`
+}
+
+// Blue/green rollout settings
+type BlueGreenConfig struct {
+	// Wait for the spring.dante-lor.github.io/promote annotation to be set to the new version's
+	// template hash before switching traffic to it
+	RequireApproval bool `json:"requireApproval,omitempty"`
+
+	// +kubebuilder:validation:Pattern=`^([0-9]+(ms|s|m|h))+$`
+	// +kubebuilder:default="30m"
+	// How long the previous version is kept running after the switch, so traffic can be switched back
+	RetentionPeriod string `json:"retentionPeriod,omitempty"`
+}
+
+// How new versions of the application are rolled out
+type RolloutConfig struct {
+	// +kubebuilder:validation:Enum=rollingUpdate;canary;blueGreen
+	// +kubebuilder:default=rollingUpdate
+	// Rollout strategy. A canary runs the new version next to the current one, checking its health and
+	// error rate at each step before promoting it. Blue/green brings up the new version behind a preview
+	// service and switches all traffic to it once it is ready
+	Strategy RolloutStrategy `json:"strategy,omitempty"`
+
+	// Canary settings, used with the canary strategy
+	Canary *CanaryConfig `json:"canary,omitempty"`
+
+	// Blue/green settings, used with the blueGreen strategy
+	BlueGreen *BlueGreenConfig `json:"blueGreen,omitempty"`
+
+	// Max number (or percentage) of extra pods created during a rolling update. Defaults depend on the
+	// application type
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+
+	// Max number (or percentage) of pods which can be unavailable during a rolling update. Defaults to 0
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+	// Seconds a new pod must be ready for before it counts as available. Defaults depend on the
+	// application type
+	MinReadySeconds *int32 `json:"minReadySeconds,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	// Seconds a rollout can go without progress before it counts as failed. Defaults depend on the
+	// application type
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+}
+
+// Limits how many pods can be taken down at once by voluntary disruptions such as node drains.
+// Only one of maxUnavailable and minAvailable may be set
+type DisruptionConfig struct {
+	// Max number (or percentage) of pods which can be unavailable. Defaults to 1
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// Min number (or percentage) of pods which must stay available
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+}
+
+// Spring boot actuator settings
+type ManagementConfig struct {
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// Port to serve actuator on, kept off the public service. Uses the application port if not set
+	Port int `json:"port,omitempty"`
+
+	// +kubebuilder:validation:Pattern=`^/`
+	// Base path of the actuator endpoints. Spring uses /actuator if not set
+	BasePath string `json:"basePath,omitempty"`
+
+	// Actuator endpoints to expose over http. Health is always exposed since the probes rely on it
+	Endpoints []string `json:"endpoints,omitempty"`
+}
+
+// Prometheus metrics settings. The application needs micrometer-registry-prometheus on its classpath
+type MetricsConfig struct {
+	// +kubebuilder:validation:Pattern=`^([0-9]+(ms|s|m|h))+$`
+	// How often the application should be scraped, for example 30s. Uses the Prometheus default if not set
+	Interval string `json:"interval,omitempty"`
+
+	// Extra labels for the ServiceMonitor, for example to match the serviceMonitorSelector of your Prometheus
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+type GarbageCollector string
+
+const (
+	G1GC       GarbageCollector = "g1"
+	ParallelGC GarbageCollector = "parallel"
+	SerialGC   GarbageCollector = "serial"
+	ZGC        GarbageCollector = "zgc"
+)
+
+// JVM tuning flags, passed to the application using JAVA_TOOL_OPTIONS. Defaults are based on the
+// resource preset. Not used for native images.
+type JVMConfig struct {
+	// +kubebuilder:validation:Minimum=10
+	// +kubebuilder:validation:Maximum=95
+	// Percentage of the memory limit to use for the heap. Defaults to 65 for small, 70 for medium and
+	// custom resources and 75 for large, leaving the rest for metaspace, thread stacks and direct buffers
+	HeapPercentage *int32 `json:"heapPercentage,omitempty"`
+
+	// +kubebuilder:validation:Enum=g1;parallel;serial;zgc
+	// Garbage collector to use. Defaults to g1
+	GC GarbageCollector `json:"gc,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	// Number of CPUs the JVM sizes its thread pools and GC threads for. Defaults to the CPU request,
+	// rounded up, with a minimum of 2. Without a CPU limit the JVM would otherwise see every CPU on the node
+	ActiveProcessorCount *int32 `json:"activeProcessorCount,omitempty"`
+
+	// +kubebuilder:validation:Pattern=`^/`
+	// Writes a heap dump to this path when the JVM runs out of memory. Must be on a writable volume,
+	// for example /tmp or one of the additional volumes
+	HeapDumpPath string `json:"heapDumpPath,omitempty"`
+
+	// Additional JVM flags, added after the generated ones
+	ExtraFlags []string `json:"extraFlags,omitempty"`
+}
+
+// Graceful shutdown settings. The pod's termination grace period is worked out from these
+type ShutdownConfig struct {
+	// +kubebuilder:validation:Minimum=1
+	// Max seconds spring waits for in flight requests to finish before stopping. Defaults to 30
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+	// Seconds to wait before shutting down, so that the pod is removed from the service endpoints
+	// and load balancers before it stops accepting requests. Defaults to 5
+	PreStopSeconds *int32 `json:"preStopSeconds,omitempty"`
+}
+
+type ProbeType string
+
+const (
+	ProbeHTTP ProbeType = "http"
+	ProbeTCP  ProbeType = "tcp"
+	ProbeExec ProbeType = "exec"
+	ProbeGRPC ProbeType = "grpc"
+)
+
+// Overrides for one of the container's probes. Timings which aren't set use the defaults for the application type
+type ProbeConfig struct {
+	// +kubebuilder:validation:Enum=http;tcp;exec;grpc
+	// +kubebuilder:default=http
+	// How the application is checked. Defaults to calling the probe's actuator health group, use tcp, exec
+	// or grpc for applications which don't serve actuator over HTTP
+	Type ProbeType `json:"type,omitempty"`
+
+	// +kubebuilder:validation:Pattern=`^/`
+	// Path called by http probes. Defaults to the actuator health group for the probe
+	Path string `json:"path,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// Port checked by http, tcp and grpc probes. Defaults to the management port for http probes and
+	// spec.port otherwise
+	Port int32 `json:"port,omitempty"`
+
+	// Command run by exec probes, which pass when it exits with 0
+	Command []string `json:"command,omitempty"`
+
+	// Service name sent in grpc health checks
+	Service string `json:"service,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+	// Seconds to wait after the container starts before probing
+	InitialDelaySeconds *int32 `json:"initialDelaySeconds,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	// Seconds between probes
+	PeriodSeconds *int32 `json:"periodSeconds,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	// Seconds before a probe times out
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	// Failed probes in a row before the probe fails
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+
+	// +kubebuilder:validation:Minimum=1
+	// Passed probes in a row before the probe passes again. Must be 1 for liveness and startup probes
+	SuccessThreshold *int32 `json:"successThreshold,omitempty"`
+}
+
+// Overrides for the container's probes
+type ProbesConfig struct {
+	// +kubebuilder:validation:Minimum=1
+	// Seconds the application usually takes to start. The startup probe must allow longer than this, and its
+	// failure threshold is raised to allow twice as long if not set. Defaults depend on the application type
+	ExpectedStartupSeconds *int32 `json:"expectedStartupSeconds,omitempty"`
+
+	// Restarts the container when it fails
+	Liveness *ProbeConfig `json:"liveness,omitempty"`
+
+	// Takes the pod out of the service when it fails
+	Readiness *ProbeConfig `json:"readiness,omitempty"`
+
+	// Holds off the other probes until the application has started
+	Startup *ProbeConfig `json:"startup,omitempty"`
+}
+
+// Settings for the writable volume mounted at /tmp
+type TmpVolumeConfig struct {
+	// Max size of /tmp. Defaults to a size based on the resource preset
+	SizeLimit *resource.Quantity `json:"sizeLimit,omitempty"`
+
+	// Use a memory backed (tmpfs) volume. Files written to it count towards the memory limit
+	Memory bool `json:"memory,omitempty"`
+}
+
+// An additional volume to mount into the application container. Exactly one source must be set
+type VolumeConfig struct {
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	// Name of the volume
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Pattern=`^/`
+	// Absolute path to mount the volume at
+	MountPath string `json:"mountPath"`
+
+	// Path within the volume to mount instead of its root
+	SubPath string `json:"subPath,omitempty"`
+
+	// Mount the volume read only
+	ReadOnly bool `json:"readOnly,omitempty"`
+
+	// Scratch space which lives as long as the pod
+	EmptyDir *corev1.EmptyDirVolumeSource `json:"emptyDir,omitempty"`
+
+	// Existing PersistentVolumeClaim
+	PersistentVolumeClaim *corev1.PersistentVolumeClaimVolumeSource `json:"persistentVolumeClaim,omitempty"`
+
+	// ConfigMap to mount as files
+	ConfigMap *corev1.ConfigMapVolumeSource `json:"configMap,omitempty"`
+
+	// Secret to mount as files
+	Secret *corev1.SecretVolumeSource `json:"secret,omitempty"`
 }
 
 // SpringBootApplicationSpec defines the desired state of SpringBootApplication.
@@ -92,6 +540,18 @@ type SpringBootApplicationSpec struct {
 	// Application.yaml file contents
 	Config *runtime.RawExtension `json:"config,omitempty"`
 
+	// Application.properties file contents, as an alternative to config. Keys such as a.b[0].c are
+	// converted to the equivalent yaml
+	ConfigProperties string `json:"configProperties,omitempty"`
+
+	// +kubebuilder:validation:items:Pattern=`^[a-zA-Z0-9][-a-zA-Z0-9_.]*$`
+	// Spring profiles to activate, set using SPRING_PROFILES_ACTIVE
+	Profiles []string `json:"profiles,omitempty"`
+
+	// Profile specific configuration, keyed by profile name. Each one is added to the ConfigMap as
+	// application-<profile>.yaml and overrides config when the profile is active
+	ProfileConfigs map[string]runtime.RawExtension `json:"profileConfigs,omitempty"`
+
 	// +kubebuilder:validation:Enum=small;medium;large
 	// Resource preset
 	ResourcePreset *ResourcePreset `json:"resourcePreset,omitempty"`
@@ -99,17 +559,174 @@ type SpringBootApplicationSpec struct {
 	// Custom resources object - you can use this instead of using the preset.
 	Resources *ResourceDefinition `json:"resources,omitempty"`
 
+	// JVM tuning. Ignored for native images
+	JVM *JVMConfig `json:"jvm,omitempty"`
+
+	// +kubebuilder:validation:Minimum=0
+	// Number of replicas to run when autoscaling is disabled, defaulting to 1. When autoscaling, this
+	// overrides the autoscaler's min replicas. Set by kubectl scale.
+	Replicas *int32 `json:"replicas,omitempty"`
+
 	// Autoscaling configuration
 	Autoscaler AutoscalingConfig `json:"autoscaler,omitempty"`
+
+	// Disruption budget settings. No budget is created when the app runs a single replica
+	Disruption *DisruptionConfig `json:"disruption,omitempty"`
+
+	// Actuator settings, for example to serve the probes and metrics on a separate port
+	Management *ManagementConfig `json:"management,omitempty"`
+
+	// Exposes actuator metrics to Prometheus using a ServiceMonitor, or scrape annotations if the
+	// Prometheus Operator isn't installed
+	Metrics *MetricsConfig `json:"metrics,omitempty"`
+
+	// Exposes the application outside of the cluster using an Ingress or HTTPRoute
+	Expose *ExposeConfig `json:"expose,omitempty"`
+
+	// Additional environment variables for the application container
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// Additional sources of environment variables for the application container
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+
+	// Secrets to load as additional Spring configuration files. Use these for credentials
+	// rather than putting them in the config field.
+	Secrets []SecretConfigSource `json:"secrets,omitempty"`
+
+	// Shared configuration to load from existing ConfigMaps and Secrets. These are loaded before config,
+	// so the application's own configuration takes precedence. Later entries override earlier ones.
+	ConfigFrom []ConfigFromSource `json:"configFrom,omitempty"`
+
+	// Graceful shutdown settings
+	Shutdown *ShutdownConfig `json:"shutdown,omitempty"`
+
+	// Probe overrides. Timings default to ones suited to the application type
+	Probes *ProbesConfig `json:"probes,omitempty"`
+
+	// Settings for the writable /tmp volume. The root filesystem is read only, so /tmp is always mounted
+	Tmp *TmpVolumeConfig `json:"tmp,omitempty"`
+
+	// Additional volumes to mount into the application container
+	Volumes []VolumeConfig `json:"volumes,omitempty"`
+
+	// How new versions are rolled out
+	Rollout *RolloutConfig `json:"rollout,omitempty"`
+
+	// +kubebuilder:default=true
+	// Restart the application when its configuration or config secrets change. Disable this if the
+	// application reloads its configuration at runtime, for example with Spring Cloud refresh.
+	RestartOnConfigChange *bool `json:"restartOnConfigChange,omitempty"`
 }
 
 // SpringBootApplicationStatus defines the observed state of SpringBootApplication.
 type SpringBootApplicationStatus struct {
 	Conditions []metav1.Condition `json:"conditions"`
+
+	// URL the application is exposed on, if any
+	URL string `json:"url,omitempty"`
+
+	// URL the application can be reached on from inside the cluster
+	ServiceURL string `json:"serviceURL,omitempty"`
+
+	// Image currently rolled out to all replicas
+	Image string `json:"image,omitempty"`
+
+	// Number of replicas currently running
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// Label selector for the application's pods, used by the scale subresource
+	Selector string `json:"selector,omitempty"`
+
+	// Number of replicas ready to serve traffic
+	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
+
+	// Number of replicas wanted by the deployment or autoscaler
+	DesiredReplicas int32 `json:"desiredReplicas,omitempty"`
+
+	// Number of replicas as last seen by the autoscaler
+	AutoscalerReplicas int32 `json:"autoscalerReplicas,omitempty"`
+
+	// Name of the scaling schedule currently in effect, if any
+	ActiveSchedule string `json:"activeSchedule,omitempty"`
+
+	// Progress of the current canary or blue/green rollout
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+
+	// The last version of the deployment which rolled out successfully
+	LastGoodRevision *DeploymentRevision `json:"lastGoodRevision,omitempty"`
+
+	// Image which failed to roll out and was rolled back
+	FailedImage string `json:"failedImage,omitempty"`
+
+	// Hash of the pod template which failed to roll out. It won't be rolled out again until the spec changes
+	FailedTemplateHash string `json:"failedTemplateHash,omitempty"`
+}
+
+// A revision of the application's deployment
+type DeploymentRevision struct {
+	// Revision number set on the deployment by Kubernetes
+	Revision string `json:"revision,omitempty"`
+
+	// Image the revision runs
+	Image string `json:"image,omitempty"`
+
+	// Hash of the revision's pod template
+	TemplateHash string `json:"templateHash,omitempty"`
+}
+
+type RolloutPhase string
+
+const (
+	RolloutProgressing      RolloutPhase = "Progressing"
+	RolloutAwaitingApproval RolloutPhase = "AwaitingApproval"
+	RolloutPromoted         RolloutPhase = "Promoted"
+	RolloutAborted          RolloutPhase = "Aborted"
+)
+
+// Progress of a canary or blue/green rollout
+type RolloutStatus struct {
+	// Hash of the pod template being rolled out
+	TemplateHash string `json:"templateHash,omitempty"`
+
+	// Image being rolled out
+	Image string `json:"image,omitempty"`
+
+	// Progressing, AwaitingApproval, Promoted or Aborted
+	Phase RolloutPhase `json:"phase,omitempty"`
+
+	// Index of the current step
+	Step int32 `json:"step,omitempty"`
+
+	// Percentage of traffic the new version is meant to get
+	Weight int32 `json:"weight,omitempty"`
+
+	// When the current step started
+	StepStartedAt *metav1.Time `json:"stepStartedAt,omitempty"`
+
+	// Number of times in a row the canary's metrics couldn't be read
+	MetricsFailures int32 `json:"metricsFailures,omitempty"`
+
+	// Colour of the blue/green deployment the service sends traffic to
+	ActiveColour string `json:"activeColour,omitempty"`
+
+	// When the previous blue/green deployment will be removed
+	RetainUntil *metav1.Time `json:"retainUntil,omitempty"`
+
+	// Details of the last analysis, or why the rollout was aborted
+	Message string `json:"message,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.replicas,statuspath=.status.replicas,selectorpath=.status.selector
+// +kubebuilder:resource:shortName=sba
+// +kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`
+// +kubebuilder:printcolumn:name="Ready",type=integer,JSONPath=`.status.readyReplicas`
+// +kubebuilder:printcolumn:name="Desired",type=integer,JSONPath=`.status.desiredReplicas`
+// +kubebuilder:printcolumn:name="Available",type=string,JSONPath=`.status.conditions[?(@.type=="Available")].status`
+// +kubebuilder:printcolumn:name="Image",type=string,JSONPath=`.status.image`,priority=1
+// +kubebuilder:printcolumn:name="URL",type=string,JSONPath=`.status.url`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // SpringBootApplication is the Schema for the springbootapplications API.
 type SpringBootApplication struct {
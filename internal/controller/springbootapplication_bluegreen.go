@@ -0,0 +1,362 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	springv1alpha1 "github.com/dante-lor/spring-boot-operator/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	COLOUR_LABEL       = "spring.dante-lor.github.io/colour"
+	PROMOTE_ANNOTATION = "spring.dante-lor.github.io/promote"
+
+	BLUE  = "blue"
+	GREEN = "green"
+)
+
+func blueGreenEnabled(app *springv1alpha1.SpringBootApplication) bool {
+	return app.Spec.Rollout != nil && app.Spec.Rollout.Strategy == springv1alpha1.BlueGreen
+}
+
+// blueGreenSettings is the blue/green config with defaults for anything not set
+func blueGreenSettings(app *springv1alpha1.SpringBootApplication) springv1alpha1.BlueGreenConfig {
+	settings := springv1alpha1.BlueGreenConfig{}
+
+	if app.Spec.Rollout != nil && app.Spec.Rollout.BlueGreen != nil {
+		settings = *app.Spec.Rollout.BlueGreen
+	}
+
+	if settings.RetentionPeriod == "" {
+		settings.RetentionPeriod = "30m"
+	}
+
+	return settings
+}
+
+// activeColour is the colour the service sends traffic to, or an empty string if the application isn't
+// using blue/green deployments yet
+func activeColour(app *springv1alpha1.SpringBootApplication) string {
+	if !blueGreenEnabled(app) || app.Status.Rollout == nil {
+		return ""
+	}
+
+	return app.Status.Rollout.ActiveColour
+}
+
+func previewColour(app *springv1alpha1.SpringBootApplication) string {
+	if activeColour(app) == BLUE {
+		return GREEN
+	}
+
+	return BLUE
+}
+
+func colourDeploymentName(app *springv1alpha1.SpringBootApplication, colour string) string {
+	return app.Name + "-" + colour
+}
+
+func previewServiceName(app *springv1alpha1.SpringBootApplication) string {
+	return app.Name + "-preview"
+}
+
+// activeDeploymentName is the deployment serving traffic, which the autoscaler and status follow
+func activeDeploymentName(app *springv1alpha1.SpringBootApplication) string {
+	if colour := activeColour(app); colour != "" {
+		return colourDeploymentName(app, colour)
+	}
+
+	return app.Name
+}
+
+// serviceSelector selects the pods the service sends traffic to. With blue/green deployments only the
+// active colour is selected
+func serviceSelector(app *springv1alpha1.SpringBootApplication) map[string]string {
+	selector := map[string]string{
+		"app": app.Name,
+	}
+
+	if colour := activeColour(app); colour != "" {
+		selector[COLOUR_LABEL] = colour
+	}
+
+	return selector
+}
+
+// ensureBlueGreen brings up a new pod template as the preview colour, next to the active colour which keeps
+// serving traffic. Once the preview is ready, and approved if required, the service is switched over to it
+// and the previous colour is kept for the retention period so traffic can be switched straight back.
+// Returns how long until the rollout needs checking again.
+func (r *SpringBootApplicationReconciler) ensureBlueGreen(ctx context.Context, app *springv1alpha1.SpringBootApplication, desired appsv1.Deployment, hash string) (time.Duration, error) {
+	if app.Status.Rollout == nil {
+		app.Status.Rollout = &springv1alpha1.RolloutStatus{}
+	}
+
+	status := app.Status.Rollout
+	settings := blueGreenSettings(app)
+
+	active := &appsv1.Deployment{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: app.Namespace, Name: activeDeploymentName(app)}, active)
+
+	if client.IgnoreNotFound(err) != nil {
+		return 0, err
+	}
+
+	// Nothing is serving traffic yet, so there's nothing to preview against
+	if err != nil {
+		colour := status.ActiveColour
+
+		if colour == "" {
+			colour = BLUE
+		}
+
+		status.ActiveColour = colour
+		status.TemplateHash = hash
+		status.Image = desired.Spec.Template.Spec.Containers[0].Image
+		status.Phase = springv1alpha1.RolloutPromoted
+		status.Message = fmt.Sprintf("Deployed the first version as %s", colour)
+
+		if _, err := r.ensureColourDeployment(ctx, app, colour, desired, hash, desired.Spec.Replicas); err != nil {
+			return 0, err
+		}
+
+		if err := r.switchTraffic(ctx, app); err != nil {
+			return 0, err
+		}
+
+		return 0, r.ensurePreviewService(ctx, app)
+	}
+
+	retention, err := time.ParseDuration(settings.RetentionPeriod)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if status.ActiveColour == "" {
+		return r.migrateToBlueGreen(ctx, app, active, retention)
+	}
+
+	if active.Annotations[TEMPLATE_HASH_ANNOTATION] == hash {
+		return r.retainPreviousColour(ctx, app, desired, hash)
+	}
+
+	preview := previewColour(app)
+
+	if status.TemplateHash != hash {
+		status.TemplateHash = hash
+		status.Image = desired.Spec.Template.Spec.Containers[0].Image
+		status.Phase = springv1alpha1.RolloutProgressing
+		status.RetainUntil = nil
+	}
+
+	// The preview runs at full size, so it can take all the traffic as soon as the service is switched
+	previewDeploy, err := r.ensureColourDeployment(ctx, app, preview, desired, hash, active.Spec.Replicas)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if err := r.ensurePreviewService(ctx, app); err != nil {
+		return 0, err
+	}
+
+	if !rolloutComplete(previewDeploy) {
+		status.Message = fmt.Sprintf("Waiting for %s to become ready", preview)
+		return CANARY_CHECK_INTERVAL, nil
+	}
+
+	if settings.RequireApproval && app.Annotations[PROMOTE_ANNOTATION] != hash {
+		status.Phase = springv1alpha1.RolloutAwaitingApproval
+		status.Message = fmt.Sprintf("%s is ready on %s, set the %s annotation to %s to switch traffic to it", preview, previewServiceName(app), PROMOTE_ANNOTATION, hash)
+		return 0, nil
+	}
+
+	status.ActiveColour = preview
+	status.Phase = springv1alpha1.RolloutPromoted
+	status.RetainUntil = &metav1.Time{Time: time.Now().Add(retention)}
+	status.Message = fmt.Sprintf("Switched traffic to %s", preview)
+
+	if err := r.switchTraffic(ctx, app); err != nil {
+		return 0, err
+	}
+
+	return retention, r.ensurePreviewService(ctx, app)
+}
+
+// migrateToBlueGreen moves the version the deployment is running onto blue, so that later versions can be
+// previewed. Both run the same version, so the service sends traffic to both until blue is ready.
+func (r *SpringBootApplicationReconciler) migrateToBlueGreen(ctx context.Context, app *springv1alpha1.SpringBootApplication, current *appsv1.Deployment, retention time.Duration) (time.Duration, error) {
+	status := app.Status.Rollout
+	hash := current.Annotations[TEMPLATE_HASH_ANNOTATION]
+
+	blue, err := r.ensureColourDeployment(ctx, app, BLUE, *current, hash, current.Spec.Replicas)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if err := r.ensurePreviewService(ctx, app); err != nil {
+		return 0, err
+	}
+
+	if !rolloutComplete(blue) {
+		status.Phase = springv1alpha1.RolloutProgressing
+		status.Message = "Waiting for blue to become ready before switching to blue/green deployments"
+		return CANARY_CHECK_INTERVAL, nil
+	}
+
+	status.ActiveColour = BLUE
+	status.TemplateHash = hash
+	status.Image = current.Spec.Template.Spec.Containers[0].Image
+	status.Phase = springv1alpha1.RolloutPromoted
+	status.RetainUntil = &metav1.Time{Time: time.Now().Add(retention)}
+	status.Message = "Switched traffic to blue"
+
+	if err := r.switchTraffic(ctx, app); err != nil {
+		return 0, err
+	}
+
+	// Any new version is previewed on green straight away
+	return CANARY_CHECK_INTERVAL, r.ensurePreviewService(ctx, app)
+}
+
+// switchTraffic points the service at the active colour in the status. The status is saved first, otherwise
+// a failure later in the reconcile would leave the previous colour in the status and the next reconcile
+// would switch traffic back to it
+func (r *SpringBootApplicationReconciler) switchTraffic(ctx context.Context, app *springv1alpha1.SpringBootApplication) error {
+	if err := r.Status().Update(ctx, app); err != nil {
+		return err
+	}
+
+	return r.ensureService(ctx, app)
+}
+
+// retainPreviousColour keeps the active colour up to date and removes the previous colour once the
+// retention period is over
+func (r *SpringBootApplicationReconciler) retainPreviousColour(ctx context.Context, app *springv1alpha1.SpringBootApplication, desired appsv1.Deployment, hash string) (time.Duration, error) {
+	status := app.Status.Rollout
+
+	if _, err := r.ensureColourDeployment(ctx, app, status.ActiveColour, desired, hash, desired.Spec.Replicas); err != nil {
+		return 0, err
+	}
+
+	if err := r.ensurePreviewService(ctx, app); err != nil {
+		return 0, err
+	}
+
+	// The spec was changed back to the version which is already active
+	if status.TemplateHash != hash {
+		status.TemplateHash = hash
+		status.Image = desired.Spec.Template.Spec.Containers[0].Image
+		status.Phase = springv1alpha1.RolloutPromoted
+		status.Message = fmt.Sprintf("%s is already serving this version", status.ActiveColour)
+	}
+
+	if status.RetainUntil != nil {
+		if remaining := time.Until(status.RetainUntil.Time); remaining > 0 {
+			return remaining, nil
+		}
+	}
+
+	status.RetainUntil = nil
+
+	err := r.deleteOwned(ctx, app, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: colourDeploymentName(app, previewColour(app))}})
+
+	if err != nil {
+		return 0, err
+	}
+
+	// The deployment used before switching to blue/green
+	return 0, r.deleteOwned(ctx, app, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: app.Name}})
+}
+
+// ensureColourDeployment runs the pod template as the given colour. Replicas are left alone when not set,
+// so the autoscaler can manage them
+func (r *SpringBootApplicationReconciler) ensureColourDeployment(ctx context.Context, app *springv1alpha1.SpringBootApplication, colour string, desired appsv1.Deployment, hash string, replicas *int32) (*appsv1.Deployment, error) {
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      colourDeploymentName(app, colour),
+			Namespace: app.Namespace,
+		},
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, deploy, func() error {
+		template := *desired.Spec.Template.DeepCopy()
+		template.Labels[COLOUR_LABEL] = colour
+
+		existingReplicas := deploy.Spec.Replicas
+
+		deploy.Labels = desired.Labels
+		deploy.Spec = *desired.Spec.DeepCopy()
+		deploy.Spec.Selector = &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				"app":        app.Name,
+				COLOUR_LABEL: colour,
+			},
+		}
+		deploy.Spec.Template = template
+		deploy.Spec.Replicas = replicas
+
+		if deploy.Spec.Replicas == nil {
+			deploy.Spec.Replicas = existingReplicas
+		}
+
+		metav1.SetMetaDataAnnotation(&deploy.ObjectMeta, TEMPLATE_HASH_ANNOTATION, hash)
+
+		return controllerutil.SetControllerReference(app, deploy, r.Scheme)
+	})
+
+	return deploy, err
+}
+
+// ensurePreviewService sends traffic to the colour which isn't active, so a new version can be tested
+// before the switch
+func (r *SpringBootApplicationReconciler) ensurePreviewService(ctx context.Context, app *springv1alpha1.SpringBootApplication) error {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      previewServiceName(app),
+			Namespace: app.Namespace,
+		},
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, svc, func() error {
+		svc.Labels = serviceLabels(app)
+		svc.Spec = createServiceSpec(app, map[string]string{
+			"app":        app.Name,
+			COLOUR_LABEL: previewColour(app),
+		})
+
+		return controllerutil.SetControllerReference(app, svc, r.Scheme)
+	})
+
+	return err
+}
+
+// removeBlueGreen cleans up after switching to another strategy. The colours keep serving traffic until
+// the deployment has taken over
+func (r *SpringBootApplicationReconciler) removeBlueGreen(ctx context.Context, app *springv1alpha1.SpringBootApplication, deploy *appsv1.Deployment) error {
+	if err := r.deleteOwned(ctx, app, &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: previewServiceName(app)}}); err != nil {
+		return err
+	}
+
+	if !rolloutComplete(deploy) {
+		return nil
+	}
+
+	for _, colour := range []string{BLUE, GREEN} {
+		err := r.deleteOwned(ctx, app, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: colourDeploymentName(app, colour)}})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,364 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	springv1alpha1 "github.com/dante-lor/spring-boot-operator/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	TEMPLATE_HASH_ANNOTATION = "spring.dante-lor.github.io/template-hash"
+	TRACK_LABEL              = "spring.dante-lor.github.io/track"
+
+	// How often a canary is checked while it isn't ready yet
+	CANARY_CHECK_INTERVAL = 15 * time.Second
+
+	// How many times in a row reading the canary's metrics can fail before it is aborted
+	CANARY_METRICS_ATTEMPTS = 4
+)
+
+var defaultCanarySteps = []int32{10, 50}
+
+// canarySettings is the canary config with defaults for anything not set
+func canarySettings(app *springv1alpha1.SpringBootApplication) springv1alpha1.CanaryConfig {
+	settings := springv1alpha1.CanaryConfig{}
+
+	if app.Spec.Rollout != nil && app.Spec.Rollout.Canary != nil {
+		settings = *app.Spec.Rollout.Canary
+	}
+
+	if len(settings.Steps) == 0 {
+		settings.Steps = defaultCanarySteps
+	}
+
+	if settings.StepDuration == "" {
+		settings.StepDuration = "5m"
+	}
+
+	if settings.MaxRestarts == 0 {
+		settings.MaxRestarts = 3
+	}
+
+	return settings
+}
+
+func canaryEnabled(app *springv1alpha1.SpringBootApplication) bool {
+	return app.Spec.Rollout != nil && app.Spec.Rollout.Strategy == springv1alpha1.Canary
+}
+
+func canaryName(app *springv1alpha1.SpringBootApplication) string {
+	return app.Name + "-canary"
+}
+
+// templateHash identifies a pod template, so the operator can tell which version each deployment runs
+// without comparing against the defaults the API server fills in
+func templateHash(template corev1.PodTemplateSpec) (string, error) {
+	data, err := json.Marshal(template)
+
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(data)
+
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// progressCanary runs the canary for a new pod template next to the stable deployment, moving through
+// the steps while it stays healthy. Returns true once the stable deployment should be updated to the new
+// template, along with when the canary next needs checking.
+func (r *SpringBootApplicationReconciler) progressCanary(ctx context.Context, app *springv1alpha1.SpringBootApplication, stable *appsv1.Deployment, desired appsv1.Deployment, hash string) (bool, time.Duration, error) {
+	settings := canarySettings(app)
+	status := app.Status.Rollout
+
+	if status == nil || status.TemplateHash != hash {
+		// A new version, start from the first step
+		status = &springv1alpha1.RolloutStatus{
+			TemplateHash:  hash,
+			Image:         desired.Spec.Template.Spec.Containers[0].Image,
+			Phase:         springv1alpha1.RolloutProgressing,
+			Weight:        settings.Steps[0],
+			StepStartedAt: ptr.To(metav1.Now()),
+			Message:       "Starting the canary",
+		}
+
+		app.Status.Rollout = status
+	}
+
+	// An aborted version stays on the stable deployment until the spec changes again
+	if status.Phase == springv1alpha1.RolloutAborted {
+		return false, 0, r.deleteOwned(ctx, app, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: canaryName(app)}})
+	}
+
+	if status.Phase == springv1alpha1.RolloutPromoted {
+		return true, 0, nil
+	}
+
+	canary := &appsv1.Deployment{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: app.Namespace, Name: canaryName(app)}, canary)
+
+	if client.IgnoreNotFound(err) != nil {
+		return false, 0, err
+	}
+
+	requeueAfter := CANARY_CHECK_INTERVAL
+
+	// Only analyse a canary which is running this version
+	if err == nil && canary.Annotations[TEMPLATE_HASH_ANNOTATION] == hash {
+		outcome, after, err := r.analyseCanary(ctx, app, canary, settings)
+
+		if err != nil {
+			return false, 0, err
+		}
+
+		switch outcome {
+		case canaryFailed:
+			status.Phase = springv1alpha1.RolloutAborted
+
+			return false, 0, r.deleteOwned(ctx, app, canary)
+		case canaryPassed:
+			status.Step++
+
+			if int(status.Step) >= len(settings.Steps) {
+				status.Phase = springv1alpha1.RolloutPromoted
+				status.Weight = 100
+				status.Message = "Promoted the canary"
+
+				return true, 0, r.deleteOwned(ctx, app, canary)
+			}
+
+			status.Weight = settings.Steps[status.Step]
+			status.StepStartedAt = ptr.To(metav1.Now())
+		default:
+			requeueAfter = after
+		}
+	}
+
+	if err := r.ensureCanaryDeployment(ctx, app, stable, desired, hash, status.Weight); err != nil {
+		return false, 0, err
+	}
+
+	return false, requeueAfter, nil
+}
+
+// ensureCanaryDeployment runs the new version with enough replicas to get roughly the step's share of
+// the traffic going to the service
+func (r *SpringBootApplicationReconciler) ensureCanaryDeployment(ctx context.Context, app *springv1alpha1.SpringBootApplication, stable *appsv1.Deployment, desired appsv1.Deployment, hash string, weight int32) error {
+	canary := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      canaryName(app),
+			Namespace: app.Namespace,
+		},
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, canary, func() error {
+		// The selector can't be changed, so only set it when creating the canary
+		if canary.CreationTimestamp.IsZero() {
+			canary.Spec.Selector = &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app":       app.Name,
+					TRACK_LABEL: "canary",
+				},
+			}
+		}
+
+		template := *desired.Spec.Template.DeepCopy()
+		template.Labels[TRACK_LABEL] = "canary"
+
+		canary.Labels = desired.Labels
+		metav1.SetMetaDataAnnotation(&canary.ObjectMeta, TEMPLATE_HASH_ANNOTATION, hash)
+		canary.Spec.Template = template
+		canary.Spec.Replicas = ptr.To(canaryReplicas(ptr.Deref(stable.Spec.Replicas, 1), weight))
+
+		return controllerutil.SetControllerReference(app, canary, r.Scheme)
+	})
+
+	return err
+}
+
+// canaryReplicas works out how many canary pods get the weight's share of traffic, since the service
+// balances requests across the stable and canary pods. The canary never runs more pods than the stable
+// deployment, so steps over 50% get an even split rather than many times the usual number of pods.
+func canaryReplicas(stableReplicas int32, weight int32) int32 {
+	replicas := math.Ceil(float64(stableReplicas) * float64(weight) / float64(100-weight))
+
+	return max(min(int32(replicas), stableReplicas), 1)
+}
+
+type canaryOutcome int
+
+const (
+	canaryWaiting canaryOutcome = iota
+	canaryPassed
+	canaryFailed
+)
+
+// analyseCanary checks the canary is healthy, then once the step has run for long enough, that its
+// error rate is below the limit
+func (r *SpringBootApplicationReconciler) analyseCanary(ctx context.Context, app *springv1alpha1.SpringBootApplication, canary *appsv1.Deployment, settings springv1alpha1.CanaryConfig) (canaryOutcome, time.Duration, error) {
+	status := app.Status.Rollout
+
+	if progressDeadlineExceeded(canary) {
+		status.Message = "The canary didn't become ready within the progress deadline"
+		return canaryFailed, 0, nil
+	}
+
+	pods := &corev1.PodList{}
+	err := r.List(ctx, pods, client.InNamespace(app.Namespace), client.MatchingLabels(canary.Spec.Selector.MatchLabels))
+
+	if err != nil {
+		return canaryWaiting, 0, err
+	}
+
+	for _, pod := range pods.Items {
+		for _, container := range pod.Status.ContainerStatuses {
+			if container.RestartCount >= settings.MaxRestarts {
+				status.Message = fmt.Sprintf("Canary pod %s restarted %d times", pod.Name, container.RestartCount)
+				return canaryFailed, 0, nil
+			}
+		}
+	}
+
+	if !rolloutComplete(canary) {
+		status.Message = "Waiting for the canary to become ready"
+		return canaryWaiting, CANARY_CHECK_INTERVAL, nil
+	}
+
+	stepDuration, err := time.ParseDuration(settings.StepDuration)
+
+	if err != nil {
+		return canaryWaiting, 0, err
+	}
+
+	if remaining := stepDuration - time.Since(status.StepStartedAt.Time); remaining > 0 {
+		status.Message = fmt.Sprintf("Step %d of %d is running", status.Step+1, len(settings.Steps))
+		return canaryWaiting, remaining, nil
+	}
+
+	total, errors, err := r.canaryRequestCounts(ctx, app, pods.Items)
+
+	if err != nil {
+		// The canary can't be promoted without its error rate, which could be blocked by a network policy or
+		// the metrics endpoint being turned off
+		logf.FromContext(ctx).Error(err, "Unable to read canary metrics", "name", app.Name)
+		status.MetricsFailures++
+
+		if status.MetricsFailures >= CANARY_METRICS_ATTEMPTS {
+			status.Message = fmt.Sprintf("Unable to read the canary's metrics after %d attempts: %v", status.MetricsFailures, err)
+			return canaryFailed, 0, nil
+		}
+
+		status.Message = fmt.Sprintf("Unable to read the canary's metrics (attempt %d of %d): %v", status.MetricsFailures, CANARY_METRICS_ATTEMPTS, err)
+		return canaryWaiting, CANARY_CHECK_INTERVAL, nil
+	}
+
+	status.MetricsFailures = 0
+
+	if total > 0 && errors/total*100 > float64(settings.MaxErrorPercentage) {
+		status.Message = fmt.Sprintf("%.1f%% of requests to the canary failed, more than the %d%% allowed", errors/total*100, settings.MaxErrorPercentage)
+		return canaryFailed, 0, nil
+	}
+
+	status.Message = fmt.Sprintf("Step %d passed with %.0f requests and %.0f errors", status.Step+1, total, errors)
+
+	return canaryPassed, 0, nil
+}
+
+// canaryRequestCounts totals the requests and server errors reported by actuator on the canary pods
+func (r *SpringBootApplicationReconciler) canaryRequestCounts(ctx context.Context, app *springv1alpha1.SpringBootApplication, pods []corev1.Pod) (float64, float64, error) {
+	var total, errors float64
+
+	for _, pod := range pods {
+		if pod.Status.PodIP == "" {
+			continue
+		}
+
+		url := fmt.Sprintf("http://%s%s/metrics/http.server.requests", net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(managementPort(app))), actuatorPath(app))
+
+		requests, err := r.requestCount(ctx, url)
+
+		if err != nil {
+			return 0, 0, err
+		}
+
+		serverErrors, err := r.requestCount(ctx, url+"?tag=outcome:SERVER_ERROR")
+
+		if err != nil {
+			return 0, 0, err
+		}
+
+		total += requests
+		errors += serverErrors
+	}
+
+	return total, errors, nil
+}
+
+// requestCount reads the COUNT measurement of an actuator metric. Actuator returns a 404 when there
+// haven't been any matching requests
+func (r *SpringBootApplicationReconciler) requestCount(ctx context.Context, url string) (float64, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+
+	if err != nil {
+		return 0, err
+	}
+
+	response, err := r.httpClient().Do(request)
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() { _ = response.Body.Close() }()
+
+	if response.StatusCode == http.StatusNotFound {
+		return 0, nil
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected status %d from %s", response.StatusCode, url)
+	}
+
+	metric := struct {
+		Measurements []struct {
+			Statistic string  `json:"statistic"`
+			Value     float64 `json:"value"`
+		} `json:"measurements"`
+	}{}
+
+	if err := json.NewDecoder(response.Body).Decode(&metric); err != nil {
+		return 0, err
+	}
+
+	for _, measurement := range metric.Measurements {
+		if measurement.Statistic == "COUNT" {
+			return measurement.Value, nil
+		}
+	}
+
+	return 0, nil
+}
+
+func (r *SpringBootApplicationReconciler) httpClient() *http.Client {
+	if r.HTTPClient != nil {
+		return r.HTTPClient
+	}
+
+	return &http.Client{Timeout: 5 * time.Second}
+}
@@ -0,0 +1,20 @@
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Canary replicas", func() {
+	DescribeTable("sizes the canary for its share of traffic",
+		func(stableReplicas int32, weight int32, expected int32) {
+			Expect(canaryReplicas(stableReplicas, weight)).To(Equal(expected))
+		},
+		Entry("a small share of a large deployment", int32(9), int32(10), int32(1)),
+		Entry("rounding up to a whole pod", int32(10), int32(25), int32(4)),
+		Entry("at least one pod", int32(1), int32(10), int32(1)),
+		Entry("an even split", int32(4), int32(50), int32(4)),
+		Entry("no more pods than the stable deployment", int32(10), int32(99), int32(10)),
+		Entry("one pod when the stable deployment has none", int32(0), int32(50), int32(1)),
+	)
+})
@@ -0,0 +1,143 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+
+	springv1alpha1 "github.com/dante-lor/spring-boot-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const CONFIG_FROM_MOUNT_PATH = "/config-from"
+
+// Field index on the applications, listing the ConfigMaps each one loads configuration from
+const CONFIG_MAPS_INDEX = "spec.configMapNames"
+
+func configFromKey(source springv1alpha1.ConfigFromSource) string {
+	if source.Key == "" {
+		return "application.yaml"
+	}
+
+	return source.Key
+}
+
+// configFromLocation is the spring config location of the source. Sources can share a name (a ConfigMap
+// and a Secret), so they are mounted by index
+func configFromLocation(index int, source springv1alpha1.ConfigFromSource) string {
+	location := path.Join(CONFIG_FROM_MOUNT_PATH, strconv.Itoa(index), configFromKey(source))
+
+	// Stops spring failing to start when the file is missing
+	if source.Optional {
+		location = "optional:" + location
+	}
+
+	return location
+}
+
+// createConfigFromVolume creates the volume (and its mount) holding just the config file from the source
+func createConfigFromVolume(index int, source springv1alpha1.ConfigFromSource) (corev1.Volume, corev1.VolumeMount) {
+	name := fmt.Sprintf("config-from-%d", index)
+	key := configFromKey(source)
+
+	items := []corev1.KeyToPath{
+		{
+			Key:  key,
+			Path: key,
+		},
+	}
+
+	volume := corev1.Volume{
+		Name: name,
+	}
+
+	if source.Secret != "" {
+		volume.Secret = &corev1.SecretVolumeSource{
+			SecretName: source.Secret,
+			Items:      items,
+			Optional:   ptr.To(source.Optional),
+		}
+	} else {
+		volume.ConfigMap = &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: source.ConfigMap,
+			},
+			Items:    items,
+			Optional: ptr.To(source.Optional),
+		}
+	}
+
+	mount := corev1.VolumeMount{
+		Name:      name,
+		MountPath: path.Join(CONFIG_FROM_MOUNT_PATH, strconv.Itoa(index)),
+		ReadOnly:  true,
+	}
+
+	return volume, mount
+}
+
+// configFromData reads the config file from the source for the config hash. Missing sources give no data
+func (r *SpringBootApplicationReconciler) configFromData(ctx context.Context, app *springv1alpha1.SpringBootApplication, source springv1alpha1.ConfigFromSource) ([]byte, error) {
+	key := configFromKey(source)
+
+	if source.Secret != "" {
+		secret := &corev1.Secret{}
+		err := r.Get(ctx, client.ObjectKey{Namespace: app.Namespace, Name: source.Secret}, secret)
+
+		return secret.Data[key], client.IgnoreNotFound(err)
+	}
+
+	cm := &corev1.ConfigMap{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: app.Namespace, Name: source.ConfigMap}, cm)
+
+	if client.IgnoreNotFound(err) != nil {
+		return nil, err
+	}
+
+	if data, ok := cm.Data[key]; ok {
+		return []byte(data), nil
+	}
+
+	return cm.BinaryData[key], nil
+}
+
+// appsForConfigMap finds the applications loading configuration from the given ConfigMap so they are
+// reconciled (and restarted if needed) when it changes
+func (r *SpringBootApplicationReconciler) appsForConfigMap(ctx context.Context, cm client.Object) []reconcile.Request {
+	apps := &springv1alpha1.SpringBootApplicationList{}
+
+	err := r.List(ctx, apps, client.InNamespace(cm.GetNamespace()), client.MatchingFields{CONFIG_MAPS_INDEX: cm.GetName()})
+
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "Unable to list applications for configmap", "configmap", cm.GetName())
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+
+	for _, app := range apps.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&app)})
+	}
+
+	return requests
+}
+
+// configMapNames indexes the application by the ConfigMaps it loads configuration from
+func configMapNames(obj client.Object) []string {
+	app := obj.(*springv1alpha1.SpringBootApplication)
+
+	names := []string{}
+
+	for _, source := range app.Spec.ConfigFrom {
+		if source.ConfigMap != "" {
+			names = append(names, source.ConfigMap)
+		}
+	}
+
+	return names
+}
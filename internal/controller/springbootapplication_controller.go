@@ -20,19 +20,27 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"time"
 
 	springv1alpha1 "github.com/dante-lor/spring-boot-operator/api/v1alpha1"
+	"github.com/dante-lor/spring-boot-operator/internal/properties"
 	appsv1 "k8s.io/api/apps/v1"
 	scalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/yaml"
 )
 
@@ -40,17 +48,31 @@ import (
 type SpringBootApplicationReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// HTTPClient reads metrics from canary pods. A client with a short timeout is used if it isn't set
+	HTTPClient *http.Client
 }
 
 const EXTERNAL_PORT = 80
 
+// Field index on the applications, listing the secrets each one loads configuration from
+const SECRETS_INDEX = "spec.secretNames"
+
 // +kubebuilder:rbac:groups=spring.dante-lor.github.io,resources=springbootapplications,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=spring.dante-lor.github.io,resources=springbootapplications/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=spring.dante-lor.github.io,resources=springbootapplications/finalizers,verbs=update
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=replicasets,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
 // +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=keda.sh,resources=scaledobjects,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -69,7 +91,7 @@ func (r *SpringBootApplicationReconciler) Reconcile(ctx context.Context, req ctr
 
 	logger.Info("Reconciling application", "name", app.Name, "namespace", app.Namespace)
 
-	appConfig, err := mergeConfig(app.Spec)
+	configFiles, err := generateConfigFiles(app.Spec)
 
 	if err != nil {
 		meta.SetStatusCondition(&app.Status.Conditions, metav1.Condition{
@@ -89,12 +111,27 @@ func (r *SpringBootApplicationReconciler) Reconcile(ctx context.Context, req ctr
 		})
 	}
 
+	app.Status.URL = exposedURL(app)
+
+	// Work out which scaling schedule applies now, so the autoscaler can be created with its replicas
+	schedule, nextBoundary, err := evaluateSchedules(app.Spec.Autoscaler.Schedules, time.Now())
+
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	app.Status.ActiveSchedule = ""
+
+	if schedule != nil {
+		app.Status.ActiveSchedule = schedule.Name
+	}
+
 	// Try and update status
 	if err := r.Status().Update(ctx, app); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	if err = r.ensureConfigMap(ctx, app, appConfig); err != nil {
+	if err = r.ensureConfigMap(ctx, app, configFiles); err != nil {
 		return ctrl.Result{}, err
 	}
 
@@ -102,7 +139,17 @@ func (r *SpringBootApplicationReconciler) Reconcile(ctx context.Context, req ctr
 		return ctrl.Result{}, err
 	}
 
-	if err = r.ensureDeployment(ctx, app); err != nil {
+	if err = r.ensureExposure(ctx, app); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err = r.ensureServiceMonitor(ctx, app); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	canaryRequeue, err := r.ensureDeployment(ctx, app, configFiles)
+
+	if err != nil {
 		return ctrl.Result{}, err
 	}
 
@@ -110,11 +157,26 @@ func (r *SpringBootApplicationReconciler) Reconcile(ctx context.Context, req ctr
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	if err = r.ensureDisruptionBudget(ctx, app); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err = r.updateStatus(ctx, app); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Come back when the canary needs checking or the next scaling window opens or closes, whichever is sooner
+	requeueAfter := canaryRequeue
+
+	if !nextBoundary.IsZero() && (requeueAfter == 0 || time.Until(nextBoundary) < requeueAfter) {
+		requeueAfter = time.Until(nextBoundary)
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
-// Creates Configmap using provided string for the application.yaml file
-func (r *SpringBootApplicationReconciler) ensureConfigMap(ctx context.Context, app *springv1alpha1.SpringBootApplication, config string) error {
+// Creates Configmap containing the generated application.yaml and profile config files
+func (r *SpringBootApplicationReconciler) ensureConfigMap(ctx context.Context, app *springv1alpha1.SpringBootApplication, files map[string]string) error {
 
 	// Get existing configmap
 	existing := &corev1.ConfigMap{}
@@ -134,9 +196,7 @@ func (r *SpringBootApplicationReconciler) ensureConfigMap(ctx context.Context, a
 	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, cm, func() error {
 		cm.Labels = app.Labels
 
-		cm.Data = map[string]string{
-			"application.yaml": config,
-		}
+		cm.Data = files
 		return controllerutil.SetControllerReference(app, cm, r.Scheme)
 	})
 
@@ -160,21 +220,8 @@ func (r *SpringBootApplicationReconciler) ensureService(ctx context.Context, app
 	}
 
 	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, svc, func() error {
-		svc.Labels = app.Labels
-
-		svc.Spec = corev1.ServiceSpec{
-			Type: "ClusterIP",
-			Ports: []corev1.ServicePort{
-				{
-					Name:       "http",
-					Port:       EXTERNAL_PORT,
-					TargetPort: intstr.FromInt(app.Spec.Port),
-				},
-			},
-			Selector: map[string]string{
-				"app": app.Name,
-			},
-		}
+		svc.Labels = serviceLabels(app)
+		svc.Spec = createServiceSpec(app, serviceSelector(app))
 
 		return controllerutil.SetControllerReference(app, svc, r.Scheme)
 	})
@@ -182,8 +229,35 @@ func (r *SpringBootApplicationReconciler) ensureService(ctx context.Context, app
 	return err
 }
 
+// serviceLabels are the application's labels plus the app label
+func serviceLabels(app *springv1alpha1.SpringBootApplication) map[string]string {
+	labels := map[string]string{}
+
+	for key, value := range app.Labels {
+		labels[key] = value
+	}
+
+	labels["app"] = app.Name
+
+	return labels
+}
+
+func createServiceSpec(app *springv1alpha1.SpringBootApplication, selector map[string]string) corev1.ServiceSpec {
+	return corev1.ServiceSpec{
+		Type: "ClusterIP",
+		Ports: []corev1.ServicePort{
+			{
+				Name:       "http",
+				Port:       EXTERNAL_PORT,
+				TargetPort: intstr.FromInt(app.Spec.Port),
+			},
+		},
+		Selector: selector,
+	}
+}
+
 // mergeConfig merges the user provided configuration with the configuration defined on
-// the spec (currently port and context path)
+// the spec (port, context path, shutdown and actuator settings)
 func mergeConfig(spec springv1alpha1.SpringBootApplicationSpec) (string, error) {
 	// Step 1: unmarshal RawExtension JSON into a map
 	merged := map[string]interface{}{}
@@ -196,22 +270,42 @@ func mergeConfig(spec springv1alpha1.SpringBootApplicationSpec) (string, error)
 		}
 	}
 
-	// Step 2: ensure "server" map exists
-	server, ok := merged["server"].(map[string]interface{})
-	if !ok {
-		server = map[string]interface{}{}
+	if spec.ConfigProperties != "" {
+		if len(merged) > 0 {
+			return "", fmt.Errorf("only one of config and configProperties can be set")
+		}
+
+		parsed, err := properties.Parse(spec.ConfigProperties)
+
+		if err != nil {
+			return "", fmt.Errorf("failed to parse configProperties: %w", err)
+		}
+
+		merged = parsed
 	}
 
-	// Step 3: set port and context path
-	server["port"] = spec.Port
+	// Step 2: set port and context path, replacing any other spelling of them
+	setManaged(merged, spec.Port, "server", "port")
+	mergeContextPath(merged, spec)
+
+	mergeShutdownConfig(merged, spec.Shutdown)
+
+	if err := mergeManagementConfig(merged, spec.Management); err != nil {
+		return "", err
+	}
 
-	servlet, ok := server["servlet"].(map[string]interface{})
-	if !ok {
-		servlet = map[string]interface{}{}
+	if spec.Metrics != nil {
+		if err := exposeActuatorEndpoint(merged, "prometheus"); err != nil {
+			return "", err
+		}
 	}
-	servlet["context-path"] = spec.ContextPath
 
-	merged["server"] = server
+	// Canary analysis reads the request metrics from actuator
+	if spec.Rollout != nil && spec.Rollout.Strategy == springv1alpha1.Canary {
+		if err := exposeActuatorEndpoint(merged, "metrics"); err != nil {
+			return "", err
+		}
+	}
 
 	// Step 4: marshal merged map to YAML
 	yamlBytes, err := yaml.Marshal(merged)
@@ -222,14 +316,128 @@ func mergeConfig(spec springv1alpha1.SpringBootApplicationSpec) (string, error)
 	return string(yamlBytes), nil
 }
 
+// mergeContextPath sets the context path using the property the framework reads. Servlet apps use
+// server.servlet.context-path and webflux apps use spring.webflux.base-path. A native image could be
+// built from either, so both are set for it.
+func mergeContextPath(config map[string]interface{}, spec springv1alpha1.SpringBootApplicationSpec) {
+	if spec.Type != springv1alpha1.SpringWebflux {
+		setManaged(config, spec.ContextPath, "server", "servlet", "context-path")
+	}
+
+	if spec.Type == springv1alpha1.SpringWebflux || spec.Type == springv1alpha1.SpringNative {
+		setManaged(config, spec.ContextPath, "spring", "webflux", "base-path")
+	}
+}
+
+// setManaged sets an operator managed property, replacing any other spelling of it or its parents
+func setManaged(config map[string]interface{}, value interface{}, keys ...string) {
+	current := config
+
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := properties.Take(current, key).(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+		}
+
+		current[key] = next
+		current = next
+	}
+
+	last := keys[len(keys)-1]
+
+	properties.Take(current, last)
+	current[last] = value
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *SpringBootApplicationReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	// Index the secrets and ConfigMaps each application loads, so one changing only lists the applications using it
+	indexer := mgr.GetFieldIndexer()
+
+	err := indexer.IndexField(context.Background(), &springv1alpha1.SpringBootApplication{}, SECRETS_INDEX, secretNames)
+
+	if err != nil {
+		return err
+	}
+
+	err = indexer.IndexField(context.Background(), &springv1alpha1.SpringBootApplication{}, CONFIG_MAPS_INDEX, configMapNames)
+
+	if err != nil {
+		return err
+	}
+
+	builder := ctrl.NewControllerManagedBy(mgr).
 		For(&springv1alpha1.SpringBootApplication{}).
 		Named("springbootapplication").
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.Service{}).
 		Owns(&scalingv2.HorizontalPodAutoscaler{}).
-		Complete(r)
+		Owns(&networkingv1.Ingress{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.appsForSecret)).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.appsForConfigMap))
+
+	// Only watch third party kinds when their CRDs are installed, otherwise the controller would fail to start
+	if apiAvailable(mgr.GetRESTMapper(), httpRouteGVK) {
+		builder = builder.Owns(newHTTPRoute(&springv1alpha1.SpringBootApplication{}))
+	}
+
+	if apiAvailable(mgr.GetRESTMapper(), scaledObjectGVK) {
+		builder = builder.Owns(newScaledObject(&springv1alpha1.SpringBootApplication{}))
+	}
+
+	if apiAvailable(mgr.GetRESTMapper(), serviceMonitorGVK) {
+		builder = builder.Owns(newServiceMonitor(&springv1alpha1.SpringBootApplication{}))
+	}
+
+	return builder.Complete(r)
+}
+
+// appsForSecret finds the applications loading configuration from the given secret so they are
+// reconciled (and restarted if needed) when it changes
+func (r *SpringBootApplicationReconciler) appsForSecret(ctx context.Context, secret client.Object) []reconcile.Request {
+	apps := &springv1alpha1.SpringBootApplicationList{}
+
+	err := r.List(ctx, apps, client.InNamespace(secret.GetNamespace()), client.MatchingFields{SECRETS_INDEX: secret.GetName()})
+
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "Unable to list applications for secret", "secret", secret.GetName())
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+
+	for _, app := range apps.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&app)})
+	}
+
+	return requests
+}
+
+// secretNames indexes the application by the secrets it loads configuration from
+func secretNames(obj client.Object) []string {
+	app := obj.(*springv1alpha1.SpringBootApplication)
+
+	names := []string{}
+
+	for _, source := range app.Spec.Secrets {
+		names = append(names, source.Name)
+	}
+
+	for _, source := range app.Spec.ConfigFrom {
+		if source.Secret != "" {
+			names = append(names, source.Secret)
+		}
+	}
+
+	return names
+}
+
+// apiAvailable checks whether the cluster serves the given kind, used for optional integrations
+// that depend on third party CRDs
+func apiAvailable(mapper meta.RESTMapper, gvk schema.GroupVersionKind) bool {
+	_, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+
+	return err == nil
 }
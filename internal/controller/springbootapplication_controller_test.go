@@ -18,18 +18,25 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 
 	springv1alpha1 "github.com/dante-lor/spring-boot-operator/api/v1alpha1"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
@@ -99,6 +106,31 @@ var _ = Describe("SpringBootApplication Controller", func() {
 
 		var resource *springv1alpha1.SpringBootApplication
 
+		reconcileAndRefresh := func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+		}
+
+		// There is no deployment controller in the test environment, so fake a finished rollout
+		markRolledOut := func(name string) {
+			deploy := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, deploy)).To(Succeed())
+
+			replicas := ptr.Deref(deploy.Spec.Replicas, 1)
+			deploy.Status = appsv1.DeploymentStatus{
+				ObservedGeneration: deploy.Generation,
+				Replicas:           replicas,
+				UpdatedReplicas:    replicas,
+				ReadyReplicas:      replicas,
+				AvailableReplicas:  replicas,
+			}
+			Expect(k8sClient.Status().Update(ctx, deploy)).To(Succeed())
+		}
+
 		BeforeEach(func() {
 
 			resource = &springv1alpha1.SpringBootApplication{
@@ -128,7 +160,7 @@ var _ = Describe("SpringBootApplication Controller", func() {
 			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
 		})
 
-		It("sets application.yaml to minimal config including port", func() {
+		It("sets application.yaml to minimal config including port and graceful shutdown", func() {
 			cm := &corev1.ConfigMap{}
 
 			Expect(k8sClient.Get(ctx, typeNamespacedName, cm)).To(Succeed())
@@ -141,6 +173,12 @@ var _ = Describe("SpringBootApplication Controller", func() {
 			expected :=
 				`server:
   port: 8080
+  servlet:
+    context-path: /
+  shutdown: graceful
+spring:
+  lifecycle:
+    timeout-per-shutdown-phase: 30s
 `
 			Expect(configFileData).To(Equal(expected))
 		})
@@ -172,6 +210,12 @@ var _ = Describe("SpringBootApplication Controller", func() {
 				expected :=
 					`server:
   port: 3333
+  servlet:
+    context-path: /
+  shutdown: graceful
+spring:
+  lifecycle:
+    timeout-per-shutdown-phase: 30s
 `
 				Expect(configFileData).To(Equal(expected))
 			})
@@ -187,6 +231,895 @@ var _ = Describe("SpringBootApplication Controller", func() {
 			})
 		})
 
+		Describe("when the application uses webflux", func() {
+			BeforeEach(func() {
+				resource.Spec.Type = springv1alpha1.SpringWebflux
+				resource.Spec.ContextPath = "/api"
+
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("sets the webflux base path instead of the servlet context path", func() {
+				cm := &corev1.ConfigMap{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, cm)).To(Succeed())
+
+				expected :=
+					`server:
+  port: 8080
+  shutdown: graceful
+spring:
+  lifecycle:
+    timeout-per-shutdown-phase: 30s
+  webflux:
+    base-path: /api
+`
+				Expect(cm.Data["application.yaml"]).To(Equal(expected))
+			})
+
+			It("probes actuator under the base path", func() {
+				deploy := &appsv1.Deployment{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, deploy)).To(Succeed())
+
+				probe := deploy.Spec.Template.Spec.Containers[0].ReadinessProbe
+				Expect(probe.HTTPGet.Path).To(Equal("/api/actuator/health/readiness"))
+			})
+		})
+
+		Describe("when the config is in the properties format", func() {
+			BeforeEach(func() {
+				resource.Spec.ConfigProperties = "app.servers[0].host=a.example.com\nserver.Port=9999\n"
+
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("converts it to yaml, keeping the operator managed port", func() {
+				cm := &corev1.ConfigMap{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, cm)).To(Succeed())
+
+				expected :=
+					`app:
+  servers:
+  - host: a.example.com
+server:
+  port: 8080
+  servlet:
+    context-path: /
+  shutdown: graceful
+spring:
+  lifecycle:
+    timeout-per-shutdown-phase: 30s
+`
+				Expect(cm.Data["application.yaml"]).To(Equal(expected))
+			})
+		})
+
+		Describe("when management settings are provided", func() {
+			BeforeEach(func() {
+				resource.Spec.Management = &springv1alpha1.ManagementConfig{
+					Port:      9000,
+					BasePath:  "/manage",
+					Endpoints: []string{"info"},
+				}
+
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("adds them to the config", func() {
+				cm := &corev1.ConfigMap{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, cm)).To(Succeed())
+
+				expected :=
+					`management:
+  endpoints:
+    web:
+      base-path: /manage
+      exposure:
+        include: info,health
+  server:
+    port: 9000
+server:
+  port: 8080
+  servlet:
+    context-path: /
+  shutdown: graceful
+spring:
+  lifecycle:
+    timeout-per-shutdown-phase: 30s
+`
+				Expect(cm.Data["application.yaml"]).To(Equal(expected))
+			})
+		})
+
+		Describe("when profiles are provided", func() {
+			BeforeEach(func() {
+				resource.Spec.Profiles = []string{"prod", "eu"}
+				resource.Spec.ProfileConfigs = map[string]runtime.RawExtension{
+					"prod": {Raw: []byte(`{"logging":{"level":{"root":"warn"}}}`)},
+				}
+
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("adds the profile config files to the configmap", func() {
+				cm := &corev1.ConfigMap{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, cm)).To(Succeed())
+
+				Expect(cm.Data).To(HaveLen(2))
+				Expect(cm.Data).To(HaveKeyWithValue("application-prod.yaml", `logging:
+  level:
+    root: warn
+`))
+			})
+
+			It("activates the profiles", func() {
+				deploy := &appsv1.Deployment{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, deploy)).To(Succeed())
+
+				Expect(deploy.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+					Name:  "SPRING_PROFILES_ACTIVE",
+					Value: "prod,eu",
+				}))
+			})
+		})
+
+		Describe("when metrics are enabled", func() {
+			BeforeEach(func() {
+				resource.Spec.Metrics = &springv1alpha1.MetricsConfig{}
+
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("exposes the prometheus actuator endpoint", func() {
+				cm := &corev1.ConfigMap{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, cm)).To(Succeed())
+
+				Expect(cm.Data["application.yaml"]).To(ContainSubstring("include: health,prometheus"))
+			})
+
+			It("leaves actuator off the service", func() {
+				svc := &corev1.Service{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, svc)).To(Succeed())
+
+				Expect(svc.Spec.Ports).To(HaveLen(1))
+				Expect(svc.Labels).To(HaveKeyWithValue("app", resourceName))
+			})
+
+			It("only creates the metrics service for a ServiceMonitor", func() {
+				err := k8sClient.Get(ctx, types.NamespacedName{Name: resourceName + "-metrics", Namespace: "default"}, &corev1.Service{})
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+			})
+
+			// The Prometheus Operator isn't installed in the test environment
+			It("falls back to scrape annotations", func() {
+				deploy := &appsv1.Deployment{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, deploy)).To(Succeed())
+
+				annotations := deploy.Spec.Template.Annotations
+				Expect(annotations).To(HaveKeyWithValue("prometheus.io/scrape", "true"))
+				Expect(annotations).To(HaveKeyWithValue("prometheus.io/port", "8080"))
+				Expect(annotations).To(HaveKeyWithValue("prometheus.io/path", "/actuator/prometheus"))
+			})
+		})
+
+		Describe("when exposed through an ingress", func() {
+			BeforeEach(func() {
+				resource.Spec.ContextPath = "/api/"
+				resource.Spec.Expose = &springv1alpha1.ExposeConfig{
+					Mode:             springv1alpha1.ExposeIngress,
+					Host:             "app.example.com",
+					IngressClassName: ptr.To("nginx"),
+					TLS: &springv1alpha1.ExposeTLS{
+						SecretName: "app-tls",
+					},
+				}
+
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("creates an ingress routing the context path to the service", func() {
+				ingress := &networkingv1.Ingress{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, ingress)).To(Succeed())
+
+				Expect(ingress.Spec.IngressClassName).To(Equal(ptr.To("nginx")))
+				Expect(ingress.Spec.Rules).To(HaveLen(1))
+
+				rule := ingress.Spec.Rules[0]
+				Expect(rule.Host).To(Equal("app.example.com"))
+				Expect(rule.HTTP.Paths).To(HaveLen(1))
+				Expect(rule.HTTP.Paths[0].Path).To(Equal("/api"))
+				Expect(rule.HTTP.Paths[0].Backend.Service.Name).To(Equal(resourceName))
+
+				Expect(ingress.Spec.TLS).To(HaveExactElements(networkingv1.IngressTLS{
+					Hosts:      []string{"app.example.com"},
+					SecretName: "app-tls",
+				}))
+			})
+
+			It("reports the URL in the status", func() {
+				Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+
+				Expect(resource.Status.URL).To(Equal("https://app.example.com/api"))
+			})
+
+			It("removes the ingress when no longer exposed", func() {
+				Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+				resource.Spec.Expose = nil
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				err = k8sClient.Get(ctx, typeNamespacedName, &networkingv1.Ingress{})
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+			})
+		})
+
+		Describe("status", func() {
+			It("reports the in cluster service URL", func() {
+				Expect(resource.Status.ServiceURL).To(Equal("http://test-resource.default.svc/"))
+			})
+
+			It("reports the pod selector for the scale subresource", func() {
+				Expect(resource.Status.Selector).To(Equal("app=test-resource"))
+			})
+
+			It("reports the rollout as progressing until replicas are available", func() {
+				Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, "Progressing")).To(BeTrue())
+				Expect(meta.IsStatusConditionFalse(resource.Status.Conditions, "Available")).To(BeTrue())
+				Expect(meta.IsStatusConditionFalse(resource.Status.Conditions, "Degraded")).To(BeTrue())
+			})
+
+			It("reports the deployment replicas and image once rolled out", func() {
+				deploy := &appsv1.Deployment{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, deploy)).To(Succeed())
+
+				// There is no deployment controller in the test environment, so fake a finished rollout
+				deploy.Status = appsv1.DeploymentStatus{
+					ObservedGeneration: deploy.Generation,
+					Replicas:           1,
+					UpdatedReplicas:    1,
+					ReadyReplicas:      1,
+					AvailableReplicas:  1,
+				}
+				Expect(k8sClient.Status().Update(ctx, deploy)).To(Succeed())
+
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+				Expect(resource.Status.ReadyReplicas).To(BeEquivalentTo(1))
+				Expect(resource.Status.Image).To(Equal("test"))
+				Expect(meta.IsStatusConditionTrue(resource.Status.Conditions, "Available")).To(BeTrue())
+				Expect(meta.IsStatusConditionFalse(resource.Status.Conditions, "Progressing")).To(BeTrue())
+			})
+		})
+
+		Describe("config hash annotation", func() {
+			getConfigHash := func() string {
+				deploy := &appsv1.Deployment{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, deploy)).To(Succeed())
+
+				return deploy.Spec.Template.Annotations["spring.dante-lor.github.io/config-hash"]
+			}
+
+			It("is set on the pod template", func() {
+				Expect(getConfigHash()).NotTo(BeEmpty())
+			})
+
+			It("changes when the configuration changes", func() {
+				before := getConfigHash()
+
+				resource.Spec.Port = 3333
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(getConfigHash()).NotTo(Equal(before))
+			})
+
+			It("is removed when restarts are disabled", func() {
+				resource.Spec.RestartOnConfigChange = ptr.To(false)
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(getConfigHash()).To(BeEmpty())
+			})
+		})
+
+		Describe("when environment variables and secrets are provided", func() {
+			BeforeEach(func() {
+				resource.Spec.Env = []corev1.EnvVar{
+					{
+						Name:  "FEATURE_FLAG",
+						Value: "on",
+					},
+				}
+				resource.Spec.Secrets = []springv1alpha1.SecretConfigSource{
+					{
+						Name: "db-credentials",
+						Key:  "datasource.yaml",
+					},
+				}
+
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("adds the user variables alongside the operator ones", func() {
+				deploy := &appsv1.Deployment{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, deploy)).To(Succeed())
+
+				container := deploy.Spec.Template.Spec.Containers[0]
+
+				Expect(container.Env).To(ContainElement(corev1.EnvVar{Name: "FEATURE_FLAG", Value: "on"}))
+				Expect(container.Env).To(ContainElement(corev1.EnvVar{
+					Name:  "SPRING_CONFIG_ADDITIONAL_LOCATION",
+					Value: "/config/,/config-secrets/0/datasource.yaml",
+				}))
+			})
+
+			It("mounts the secret as a config file", func() {
+				deploy := &appsv1.Deployment{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, deploy)).To(Succeed())
+
+				podSpec := deploy.Spec.Template.Spec
+
+				Expect(podSpec.Volumes).To(HaveLen(3))
+				Expect(podSpec.Volumes[1].Secret.SecretName).To(Equal("db-credentials"))
+				Expect(podSpec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+					Name:      "secret-0",
+					MountPath: "/config-secrets/0",
+					ReadOnly:  true,
+				}))
+			})
+
+			It("requeues the application when the secret changes", func() {
+				Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+				indexed := indexedReconciler(resource)
+
+				secret := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "db-credentials",
+						Namespace: resource.Namespace,
+					},
+				}
+
+				Expect(indexed.appsForSecret(ctx, secret)).To(ConsistOf(reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				}))
+
+				secret.Name = "other-credentials"
+				Expect(indexed.appsForSecret(ctx, secret)).To(BeEmpty())
+			})
+
+			It("refuses to override operator managed variables", func() {
+				Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+				resource.Spec.Env = append(resource.Spec.Env, corev1.EnvVar{
+					Name:  "JAVA_TOOL_OPTIONS",
+					Value: "-Xmx10m",
+				})
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		Describe("when shared config sources are provided", func() {
+			var shared *corev1.ConfigMap
+
+			BeforeEach(func() {
+				shared = &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "shared-logging",
+						Namespace: "default",
+					},
+					Data: map[string]string{
+						"logging.yaml": "logging.level.root: warn",
+					},
+				}
+				Expect(k8sClient.Create(ctx, shared)).To(Succeed())
+
+				resource.Spec.ConfigFrom = []springv1alpha1.ConfigFromSource{
+					{
+						ConfigMap: "shared-logging",
+						Key:       "logging.yaml",
+					},
+					{
+						Secret:   "shared-datasource",
+						Optional: true,
+					},
+				}
+
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				Expect(k8sClient.Delete(ctx, shared)).To(Succeed())
+			})
+
+			It("loads them before the generated config", func() {
+				deploy := &appsv1.Deployment{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, deploy)).To(Succeed())
+
+				Expect(deploy.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+					Name:  "SPRING_CONFIG_ADDITIONAL_LOCATION",
+					Value: "/config-from/0/logging.yaml,optional:/config-from/1/application.yaml,/config/",
+				}))
+			})
+
+			It("mounts the config files", func() {
+				deploy := &appsv1.Deployment{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, deploy)).To(Succeed())
+
+				podSpec := deploy.Spec.Template.Spec
+
+				Expect(podSpec.Volumes).To(ContainElement(HaveField("Name", "config-from-0")))
+				Expect(podSpec.Volumes).To(ContainElement(HaveField("Secret.SecretName", "shared-datasource")))
+				Expect(podSpec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+					Name:      "config-from-0",
+					MountPath: "/config-from/0",
+					ReadOnly:  true,
+				}))
+			})
+
+			It("requeues the application when the ConfigMap changes", func() {
+				Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+				indexed := indexedReconciler(resource)
+
+				Expect(indexed.appsForConfigMap(ctx, shared)).To(ConsistOf(reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				}))
+			})
+
+			It("requeues the application when the secret changes", func() {
+				Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+				indexed := indexedReconciler(resource)
+
+				secret := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "shared-datasource",
+						Namespace: resource.Namespace,
+					},
+				}
+
+				Expect(indexed.appsForSecret(ctx, secret)).To(ConsistOf(reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				}))
+
+				// Only ConfigMaps are indexed by their ConfigMap sources
+				Expect(indexed.appsForConfigMap(ctx, &corev1.ConfigMap{
+					ObjectMeta: secret.ObjectMeta,
+				})).To(BeEmpty())
+			})
+		})
+
+		Describe("when a rollout fails", func() {
+			var goodRevision *appsv1.ReplicaSet
+
+			getImage := func() string {
+				deploy := &appsv1.Deployment{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, deploy)).To(Succeed())
+
+				return deploy.Spec.Template.Spec.Containers[0].Image
+			}
+
+			BeforeEach(func() {
+				deploy := &appsv1.Deployment{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, deploy)).To(Succeed())
+
+				// There is no deployment controller in the test environment, so fake the first revision
+				// and its ReplicaSet
+				metav1.SetMetaDataAnnotation(&deploy.ObjectMeta, "deployment.kubernetes.io/revision", "1")
+				Expect(k8sClient.Update(ctx, deploy)).To(Succeed())
+				markRolledOut(resourceName)
+
+				template := *deploy.Spec.Template.DeepCopy()
+				template.Labels["pod-template-hash"] = "good"
+
+				goodRevision = &appsv1.ReplicaSet{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      resourceName + "-good",
+						Namespace: "default",
+						Labels:    template.Labels,
+						Annotations: map[string]string{
+							"deployment.kubernetes.io/revision": "1",
+						},
+					},
+					Spec: appsv1.ReplicaSetSpec{
+						Selector: &metav1.LabelSelector{MatchLabels: template.Labels},
+						Template: template,
+					},
+				}
+				Expect(controllerutil.SetControllerReference(deploy, goodRevision, k8sClient.Scheme())).To(Succeed())
+				Expect(k8sClient.Create(ctx, goodRevision)).To(Succeed())
+
+				reconcileAndRefresh()
+
+				Expect(resource.Status.LastGoodRevision).NotTo(BeNil())
+				Expect(resource.Status.LastGoodRevision.Revision).To(Equal("1"))
+
+				// Roll out an image which never becomes ready
+				resource.Spec.Image = "test:broken"
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+				reconcileAndRefresh()
+
+				Expect(k8sClient.Get(ctx, typeNamespacedName, deploy)).To(Succeed())
+				metav1.SetMetaDataAnnotation(&deploy.ObjectMeta, "deployment.kubernetes.io/revision", "2")
+				Expect(k8sClient.Update(ctx, deploy)).To(Succeed())
+
+				deploy.Status = appsv1.DeploymentStatus{
+					ObservedGeneration: deploy.Generation,
+					Conditions: []appsv1.DeploymentCondition{
+						{
+							Type:    appsv1.DeploymentProgressing,
+							Status:  corev1.ConditionFalse,
+							Reason:  "ProgressDeadlineExceeded",
+							Message: "ReplicaSet has timed out progressing.",
+						},
+					},
+				}
+				Expect(k8sClient.Status().Update(ctx, deploy)).To(Succeed())
+
+				reconcileAndRefresh()
+			})
+
+			AfterEach(func() {
+				Expect(k8sClient.Delete(ctx, goodRevision)).To(Succeed())
+
+				// The faked deployment status would otherwise be seen by later tests
+				Expect(k8sClient.Delete(ctx, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"}})).To(Succeed())
+			})
+
+			It("rolls back to the last good revision", func() {
+				Expect(getImage()).To(Equal("test"))
+				Expect(resource.Status.FailedImage).To(Equal("test:broken"))
+
+				condition := meta.FindStatusCondition(resource.Status.Conditions, "RolledBack")
+				Expect(condition).NotTo(BeNil())
+				Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+				Expect(condition.Message).To(ContainSubstring("test:broken"))
+			})
+
+			It("doesn't retry the failed version", func() {
+				reconcileAndRefresh()
+
+				Expect(getImage()).To(Equal("test"))
+			})
+
+			It("rolls out again when the image changes", func() {
+				resource.Spec.Image = "test:fixed"
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+				reconcileAndRefresh()
+
+				Expect(getImage()).To(Equal("test:fixed"))
+				Expect(resource.Status.FailedImage).To(BeEmpty())
+				Expect(meta.FindStatusCondition(resource.Status.Conditions, "RolledBack")).To(BeNil())
+			})
+
+			It("rolls out again when other settings change", func() {
+				resource.Spec.Env = []corev1.EnvVar{{Name: "FEATURE_FLAG", Value: "off"}}
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+				reconcileAndRefresh()
+
+				Expect(getImage()).To(Equal("test:broken"))
+				Expect(resource.Status.FailedTemplateHash).To(BeEmpty())
+			})
+		})
+
+		Describe("with the canary strategy", func() {
+			canaryName := types.NamespacedName{Name: resourceName + "-canary", Namespace: "default"}
+
+			getDeployments := func() (*appsv1.Deployment, *appsv1.Deployment) {
+				stable := &appsv1.Deployment{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, stable)).To(Succeed())
+
+				canary := &appsv1.Deployment{}
+				if err := k8sClient.Get(ctx, canaryName, canary); err != nil {
+					Expect(errors.IsNotFound(err)).To(BeTrue())
+					canary = nil
+				}
+
+				return stable, canary
+			}
+
+			BeforeEach(func() {
+				resource.Spec.Image = "test:2"
+				resource.Spec.Rollout = &springv1alpha1.RolloutConfig{
+					Strategy: springv1alpha1.Canary,
+					Canary: &springv1alpha1.CanaryConfig{
+						Steps:        []int32{50},
+						StepDuration: "1ms",
+						MaxRestarts:  3,
+					},
+				}
+
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+				reconcileAndRefresh()
+			})
+
+			AfterEach(func() {
+				// There is no garbage collector in the test environment
+				canary := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: canaryName.Name, Namespace: "default"}}
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, canary))).To(Succeed())
+
+				pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: canaryName.Name + "-pod", Namespace: "default"}}
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, pod))).To(Succeed())
+			})
+
+			It("runs the new version as a canary next to the current one", func() {
+				stable, canary := getDeployments()
+
+				Expect(stable.Spec.Template.Spec.Containers[0].Image).To(Equal("test"))
+				Expect(canary).NotTo(BeNil())
+				Expect(canary.Spec.Template.Spec.Containers[0].Image).To(Equal("test:2"))
+				Expect(canary.Spec.Template.Labels).To(HaveKeyWithValue("spring.dante-lor.github.io/track", "canary"))
+				Expect(canary.Spec.Replicas).To(Equal(ptr.To[int32](1)))
+
+				Expect(resource.Status.Rollout).NotTo(BeNil())
+				Expect(resource.Status.Rollout.Phase).To(Equal(springv1alpha1.RolloutProgressing))
+				Expect(resource.Status.Rollout.Image).To(Equal("test:2"))
+				Expect(resource.Status.Rollout.Weight).To(BeEquivalentTo(50))
+			})
+
+			It("exposes the metrics actuator endpoint for the analysis", func() {
+				cm := &corev1.ConfigMap{}
+				Expect(k8sClient.Get(ctx, typeNamespacedName, cm)).To(Succeed())
+
+				Expect(cm.Data["application.yaml"]).To(ContainSubstring("include: health,metrics"))
+			})
+
+			It("aborts the canary when it doesn't become ready", func() {
+				_, canary := getDeployments()
+
+				canary.Status = appsv1.DeploymentStatus{
+					ObservedGeneration: canary.Generation,
+					Conditions: []appsv1.DeploymentCondition{
+						{
+							Type:    appsv1.DeploymentProgressing,
+							Status:  corev1.ConditionFalse,
+							Reason:  "ProgressDeadlineExceeded",
+							Message: "ReplicaSet has timed out progressing.",
+						},
+					},
+				}
+				Expect(k8sClient.Status().Update(ctx, canary)).To(Succeed())
+
+				reconcileAndRefresh()
+
+				stable, canary := getDeployments()
+				Expect(canary).To(BeNil())
+				Expect(stable.Spec.Template.Spec.Containers[0].Image).To(Equal("test"))
+				Expect(resource.Status.Rollout.Phase).To(Equal(springv1alpha1.RolloutAborted))
+			})
+
+			It("aborts the canary when its metrics can't be read", func() {
+				_, canary := getDeployments()
+
+				// There is no deployment controller in the test environment, so create the canary's pod
+				pod := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      canaryName.Name + "-pod",
+						Namespace: "default",
+						Labels:    canary.Spec.Selector.MatchLabels,
+					},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "app", Image: "test:2"}},
+					},
+				}
+				Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+
+				pod.Status.PodIP = "10.0.0.1"
+				Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+
+				markRolledOut(canaryName.Name)
+				controllerReconciler.HTTPClient = &http.Client{Transport: unreachableTransport{}}
+
+				reconcileAndRefresh()
+
+				stable, canary := getDeployments()
+				Expect(canary).NotTo(BeNil())
+				Expect(stable.Spec.Template.Spec.Containers[0].Image).To(Equal("test"))
+				Expect(resource.Status.Rollout.Phase).To(Equal(springv1alpha1.RolloutProgressing))
+				Expect(resource.Status.Rollout.Message).To(ContainSubstring("attempt 1 of 4"))
+
+				for range 3 {
+					reconcileAndRefresh()
+				}
+
+				stable, canary = getDeployments()
+				Expect(canary).To(BeNil())
+				Expect(stable.Spec.Template.Spec.Containers[0].Image).To(Equal("test"))
+				Expect(resource.Status.Rollout.Phase).To(Equal(springv1alpha1.RolloutAborted))
+			})
+
+			It("promotes the canary once every step passes", func() {
+				markRolledOut(canaryName.Name)
+
+				reconcileAndRefresh()
+
+				stable, canary := getDeployments()
+				Expect(canary).To(BeNil())
+				Expect(stable.Spec.Template.Spec.Containers[0].Image).To(Equal("test:2"))
+				Expect(resource.Status.Rollout.Phase).To(Equal(springv1alpha1.RolloutPromoted))
+			})
+		})
+
+		Describe("with the blue/green strategy", func() {
+			getService := func(name string) *corev1.Service {
+				svc := &corev1.Service{}
+				Expect(k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, svc)).To(Succeed())
+
+				return svc
+			}
+
+			getImage := func(name string) string {
+				deploy := &appsv1.Deployment{}
+				Expect(k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, deploy)).To(Succeed())
+
+				return deploy.Spec.Template.Spec.Containers[0].Image
+			}
+
+			BeforeEach(func() {
+				resource.Spec.Rollout = &springv1alpha1.RolloutConfig{
+					Strategy: springv1alpha1.BlueGreen,
+					BlueGreen: &springv1alpha1.BlueGreenConfig{
+						RequireApproval: true,
+						RetentionPeriod: "1h",
+					},
+				}
+
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+				reconcileAndRefresh()
+			})
+
+			AfterEach(func() {
+				// There is no garbage collector in the test environment
+				for _, name := range []string{resourceName + "-blue", resourceName + "-green"} {
+					deploy := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+					Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, deploy))).To(Succeed())
+				}
+
+				preview := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: resourceName + "-preview", Namespace: "default"}}
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, preview))).To(Succeed())
+			})
+
+			It("moves the running version onto blue", func() {
+				Expect(getImage(resourceName + "-blue")).To(Equal("test"))
+				Expect(getService(resourceName + "-preview").Spec.Selector).To(HaveKeyWithValue("spring.dante-lor.github.io/colour", "blue"))
+
+				// The existing deployment keeps serving traffic until blue is ready
+				Expect(getService(resourceName).Spec.Selector).To(Equal(map[string]string{"app": resourceName}))
+			})
+
+			It("switches the service to blue once it is ready", func() {
+				markRolledOut(resourceName + "-blue")
+
+				reconcileAndRefresh()
+
+				Expect(resource.Status.Rollout.ActiveColour).To(Equal("blue"))
+				Expect(getService(resourceName).Spec.Selector).To(HaveKeyWithValue("spring.dante-lor.github.io/colour", "blue"))
+				Expect(getService(resourceName + "-preview").Spec.Selector).To(HaveKeyWithValue("spring.dante-lor.github.io/colour", "green"))
+			})
+
+			It("saves the active colour before switching the service", func() {
+				// KEDA isn't installed in the test environment, so the reconcile fails after the switch
+				resource.Spec.Autoscaler.Engine = springv1alpha1.EngineKeda
+				resource.Spec.Autoscaler.Keda = &springv1alpha1.KedaConfig{
+					Triggers: []springv1alpha1.KedaTrigger{
+						{
+							Type:     "cpu",
+							Metadata: map[string]string{"value": "70"},
+						},
+					},
+				}
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+				markRolledOut(resourceName + "-blue")
+
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+				Expect(err).To(HaveOccurred())
+
+				Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+				Expect(resource.Status.Rollout.ActiveColour).To(Equal("blue"))
+				Expect(getService(resourceName).Spec.Selector).To(HaveKeyWithValue("spring.dante-lor.github.io/colour", "blue"))
+			})
+
+			It("previews a new version and switches to it once approved", func() {
+				markRolledOut(resourceName + "-blue")
+				reconcileAndRefresh()
+
+				resource.Spec.Image = "test:2"
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+				reconcileAndRefresh()
+
+				Expect(getImage(resourceName + "-green")).To(Equal("test:2"))
+
+				markRolledOut(resourceName + "-green")
+				reconcileAndRefresh()
+
+				Expect(resource.Status.Rollout.Phase).To(Equal(springv1alpha1.RolloutAwaitingApproval))
+				Expect(getService(resourceName).Spec.Selector).To(HaveKeyWithValue("spring.dante-lor.github.io/colour", "blue"))
+
+				resource.Annotations = map[string]string{
+					"spring.dante-lor.github.io/promote": resource.Status.Rollout.TemplateHash,
+				}
+				Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+				reconcileAndRefresh()
+
+				Expect(resource.Status.Rollout.Phase).To(Equal(springv1alpha1.RolloutPromoted))
+				Expect(resource.Status.Rollout.RetainUntil).NotTo(BeNil())
+				Expect(getService(resourceName).Spec.Selector).To(HaveKeyWithValue("spring.dante-lor.github.io/colour", "green"))
+
+				// Blue is kept so traffic can be switched back
+				Expect(getImage(resourceName + "-blue")).To(Equal("test"))
+			})
+		})
+
 		Describe("OwnerReferences on Sub-Resources", func() {
 
 			SubResourceHasOwnerReference := func(sub client.Object) {
@@ -219,3 +1152,26 @@ var _ = Describe("SpringBootApplication Controller", func() {
 		})
 	})
 })
+
+// unreachableTransport stands in for pods the operator can't reach
+type unreachableTransport struct{}
+
+func (unreachableTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, fmt.Errorf("connection refused")
+}
+
+// indexedReconciler looks applications up through a fake client with the field indexes the manager
+// registers, since the tests don't run the manager's cache
+func indexedReconciler(apps ...client.Object) *SpringBootApplicationReconciler {
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(k8sClient.Scheme()).
+		WithObjects(apps...).
+		WithIndex(&springv1alpha1.SpringBootApplication{}, SECRETS_INDEX, secretNames).
+		WithIndex(&springv1alpha1.SpringBootApplication{}, CONFIG_MAPS_INDEX, configMapNames).
+		Build()
+
+	return &SpringBootApplicationReconciler{
+		Client: fakeClient,
+		Scheme: fakeClient.Scheme(),
+	}
+}
@@ -2,26 +2,124 @@ package controller
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"path"
+	"strconv"
 	"strings"
+	"time"
 
 	springv1alpha1 "github.com/dante-lor/spring-boot-operator/api/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
-func (r *SpringBootApplicationReconciler) ensureDeployment(ctx context.Context, app *springv1alpha1.SpringBootApplication) error {
+const (
+	ENV_CONFIG_LOCATION   = "SPRING_CONFIG_ADDITIONAL_LOCATION"
+	ENV_JAVA_TOOL_OPTIONS = "JAVA_TOOL_OPTIONS"
+	ENV_PROFILES_ACTIVE   = "SPRING_PROFILES_ACTIVE"
+
+	CONFIG_MOUNT_PATH  = "/config"
+	SECRETS_MOUNT_PATH = "/config-secrets"
+	TMP_MOUNT_PATH     = "/tmp"
+
+	CONFIG_HASH_ANNOTATION = "spring.dante-lor.github.io/config-hash"
+)
+
+// ensureDeployment creates or updates the application's deployment. With the canary strategy, a new pod
+// template is run as a canary first and the deployment keeps its current template until it is promoted.
+// Blue/green deployments are handed off to ensureBlueGreen. Returns how long until the rollout needs
+// checking again, if one is running.
+func (r *SpringBootApplicationReconciler) ensureDeployment(ctx context.Context, app *springv1alpha1.SpringBootApplication, configFiles map[string]string) (time.Duration, error) {
 	existing := &appsv1.Deployment{}
 
 	err := r.Get(ctx, client.ObjectKeyFromObject(app), existing)
 
 	if client.IgnoreNotFound(err) != nil {
-		return err
+		return 0, err
+	}
+
+	found := err == nil
+
+	configHash, err := r.configHash(ctx, app, configFiles)
+
+	if err != nil {
+		return 0, err
+	}
+
+	podAnnotations := r.scrapeAnnotations(app)
+
+	if configHash != "" {
+		if podAnnotations == nil {
+			podAnnotations = map[string]string{}
+		}
+
+		podAnnotations[CONFIG_HASH_ANNOTATION] = configHash
+	}
+
+	desired, err := r.createDeploymentObject(app, podAnnotations)
+
+	if err != nil {
+		return 0, err
+	}
+
+	hash, err := templateHash(desired.Spec.Template)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if blueGreenEnabled(app) {
+		if err := r.deleteOwned(ctx, app, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: canaryName(app)}}); err != nil {
+			return 0, err
+		}
+
+		return r.ensureBlueGreen(ctx, app, desired, hash)
+	}
+
+	var requeueAfter time.Duration
+	var rollback *corev1.PodTemplateSpec
+
+	if found {
+		recordRevision(app, existing)
+
+		rollback, hash, err = r.rollbackTemplate(ctx, app, existing, hash)
+
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if rollback != nil {
+		desired.Spec.Template = *rollback
+	} else if canaryEnabled(app) && found && existing.Annotations[TEMPLATE_HASH_ANNOTATION] != hash {
+		promote, after, err := r.progressCanary(ctx, app, existing, desired, hash)
+
+		if err != nil {
+			return 0, err
+		}
+
+		requeueAfter = after
+
+		if !promote {
+			desired.Spec.Template = existing.Spec.Template
+			hash = existing.Annotations[TEMPLATE_HASH_ANNOTATION]
+		}
+	} else {
+		if err := r.deleteOwned(ctx, app, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: canaryName(app)}}); err != nil {
+			return 0, err
+		}
+
+		// The deployment is already running the desired template, so there's nothing in progress
+		if !canaryEnabled(app) || (app.Status.Rollout != nil && app.Status.Rollout.Phase == springv1alpha1.RolloutProgressing) {
+			app.Status.Rollout = nil
+		}
 	}
 
 	deploy := &appsv1.Deployment{
@@ -32,22 +130,74 @@ func (r *SpringBootApplicationReconciler) ensureDeployment(ctx context.Context,
 	}
 
 	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, deploy, func() error {
-		desired, err := r.createDeploymentObject(app)
-
-		if err != nil {
-			return err
-		}
+		// Keep the replica count set by the autoscaler, otherwise every update would reset it
+		replicas := deploy.Spec.Replicas
 
 		deploy.Labels = desired.Labels
 		deploy.Spec = desired.Spec
 
+		if deploy.Spec.Replicas == nil {
+			deploy.Spec.Replicas = replicas
+		}
+
+		if hash != "" {
+			metav1.SetMetaDataAnnotation(&deploy.ObjectMeta, TEMPLATE_HASH_ANNOTATION, hash)
+		}
+
 		return controllerutil.SetControllerReference(app, deploy, r.Scheme)
 	})
 
-	return err
+	if err != nil {
+		return 0, err
+	}
+
+	return requeueAfter, r.removeBlueGreen(ctx, app, deploy)
 }
 
-func (r *SpringBootApplicationReconciler) createDeploymentObject(app *springv1alpha1.SpringBootApplication) (appsv1.Deployment, error) {
+// configHash hashes the generated config files and the contents of any config secrets. The hash is
+// put on the pod template so that config changes cause a rollout. Returns an empty string if the
+// application has opted out of restarts.
+func (r *SpringBootApplicationReconciler) configHash(ctx context.Context, app *springv1alpha1.SpringBootApplication, configFiles map[string]string) (string, error) {
+	if !ptr.Deref(app.Spec.RestartOnConfigChange, true) {
+		return "", nil
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(configFiles[APPLICATION_CONFIG_FILE]))
+
+	for _, name := range sortedProfileConfigFiles(configFiles) {
+		hash.Write([]byte(name))
+		hash.Write([]byte(configFiles[name]))
+	}
+
+	for _, source := range app.Spec.Secrets {
+		secret := &corev1.Secret{}
+		err := r.Get(ctx, client.ObjectKey{Namespace: app.Namespace, Name: source.Name}, secret)
+
+		// A missing secret stops the pods from starting anyway, there's no need to block the deployment on it
+		if client.IgnoreNotFound(err) != nil {
+			return "", err
+		}
+
+		hash.Write([]byte(source.Name))
+		hash.Write(secret.Data[secretConfigKey(source)])
+	}
+
+	for i, source := range app.Spec.ConfigFrom {
+		data, err := r.configFromData(ctx, app, source)
+
+		if err != nil {
+			return "", err
+		}
+
+		hash.Write([]byte(configFromLocation(i, source)))
+		hash.Write(data)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func (r *SpringBootApplicationReconciler) createDeploymentObject(app *springv1alpha1.SpringBootApplication, podAnnotations map[string]string) (appsv1.Deployment, error) {
 	labels := app.GetLabels()
 
 	if labels == nil {
@@ -66,20 +216,51 @@ func (r *SpringBootApplicationReconciler) createDeploymentObject(app *springv1al
 		return appsv1.Deployment{}, err
 	}
 
-	// Construct the spring boot actuator path
+	liveness, readiness, startup, err := createProbes(app)
 
-	healthPath := app.Spec.ContextPath
+	if err != nil {
+		return appsv1.Deployment{}, err
+	}
 
-	if strings.HasSuffix(healthPath, "/") {
-		healthPath += "actuator/health"
-	} else {
-		healthPath += "/actuator/health"
+	ports := []corev1.ContainerPort{
+		{
+			Name:          "http",
+			ContainerPort: int32(app.Spec.Port),
+		},
+	}
+
+	if hasManagementPort(app) {
+		ports = append(ports, corev1.ContainerPort{
+			Name:          MANAGEMENT_PORT_NAME,
+			ContainerPort: int32(managementPort(app)),
+		})
+	}
+
+	env, err := createEnv(app, resources)
+
+	if err != nil {
+		return appsv1.Deployment{}, err
+	}
+
+	volumes, volumeMounts := createVolumes(app)
+
+	rollout, err := createRolloutSettings(app)
+
+	if err != nil {
+		return appsv1.Deployment{}, err
 	}
 
 	runAsNonRoot := true
 	allowPriviledgeEscalation := false
 	readOnlyFileSystem := true
 
+	// Left as nil when autoscaling, since the autoscaler sets the replicas
+	var replicas *int32
+
+	if !autoscalingEnabled(app) {
+		replicas = ptr.To(fixedReplicas(app))
+	}
+
 	dep := appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      app.Name,
@@ -87,15 +268,19 @@ func (r *SpringBootApplicationReconciler) createDeploymentObject(app *springv1al
 			Labels:    app.Labels,
 		},
 		Spec: appsv1.DeploymentSpec{
-			Replicas: nil, // This is nil because the Autoscaler will set the replicas
+			Replicas: replicas,
 			Selector: &metav1.LabelSelector{
 				MatchLabels: map[string]string{
 					"app": app.Name,
 				},
 			},
+			Strategy:                createDeploymentStrategy(rollout),
+			MinReadySeconds:         *rollout.MinReadySeconds,
+			ProgressDeadlineSeconds: rollout.ProgressDeadlineSeconds,
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: labels,
+					Labels:      labels,
+					Annotations: podAnnotations,
 				},
 				Spec: corev1.PodSpec{
 					SecurityContext: &corev1.PodSecurityContext{
@@ -106,40 +291,13 @@ func (r *SpringBootApplicationReconciler) createDeploymentObject(app *springv1al
 					},
 					Containers: []corev1.Container{
 						{
-							Name:  "app",
-							Image: app.Spec.Image,
-							Ports: []corev1.ContainerPort{
-								{
-									Name:          "http",
-									ContainerPort: int32(app.Spec.Port),
-								},
-							},
-							LivenessProbe: &corev1.Probe{
-								ProbeHandler: corev1.ProbeHandler{
-									HTTPGet: &corev1.HTTPGetAction{
-										Port: intstr.FromInt(app.Spec.Port),
-										Path: healthPath + "/liveness",
-									},
-								},
-							},
-							ReadinessProbe: &corev1.Probe{
-								ProbeHandler: corev1.ProbeHandler{
-									HTTPGet: &corev1.HTTPGetAction{
-										Port: intstr.FromInt(app.Spec.Port),
-										Path: healthPath + "/readiness",
-									},
-								},
-							},
-							StartupProbe: &corev1.Probe{
-								ProbeHandler: corev1.ProbeHandler{
-									HTTPGet: &corev1.HTTPGetAction{
-										Port: intstr.FromInt(app.Spec.Port),
-										Path: healthPath + "/liveness",
-									},
-								},
-								FailureThreshold: 30,
-							},
-							Resources: resources,
+							Name:           "app",
+							Image:          app.Spec.Image,
+							Ports:          ports,
+							LivenessProbe:  liveness,
+							ReadinessProbe: readiness,
+							StartupProbe:   startup,
+							Resources:      resources,
 							SecurityContext: &corev1.SecurityContext{
 								AllowPrivilegeEscalation: &allowPriviledgeEscalation,
 								ReadOnlyRootFilesystem:   &readOnlyFileSystem,
@@ -149,40 +307,14 @@ func (r *SpringBootApplicationReconciler) createDeploymentObject(app *springv1al
 									},
 								},
 							},
-							Env: []corev1.EnvVar{
-								{
-									// Using additional config means that this config is merged with their existing
-									// Configuration, meaning the config object doesn't have to be as large
-									Name:  "SPRING_CONFIG_ADDITIONAL_LOCATION",
-									Value: "/config",
-								},
-								{
-									// By default, java only uses 25% of it's memory for the java heap. That is quite low
-									// Setting this to 70 allows it to use more. Some needs to be left for GC.
-									Name:  "JAVA_TOOL_OPTIONS",
-									Value: "-XX:MaxRAMPercentage=70",
-								},
-							},
-							VolumeMounts: []corev1.VolumeMount{
-								{
-									Name:      "config",
-									MountPath: "/config",
-								},
-							},
-						},
-					},
-					Volumes: []corev1.Volume{
-						{
-							Name: "config",
-							VolumeSource: corev1.VolumeSource{
-								ConfigMap: &corev1.ConfigMapVolumeSource{
-									LocalObjectReference: corev1.LocalObjectReference{
-										Name: app.Name,
-									},
-								},
-							},
+							Env:          env,
+							EnvFrom:      app.Spec.EnvFrom,
+							VolumeMounts: volumeMounts,
+							Lifecycle:    createLifecycle(app.Spec.Shutdown),
 						},
 					},
+					Volumes:                       volumes,
+					TerminationGracePeriodSeconds: ptr.To(terminationGracePeriodSeconds(app.Spec.Shutdown)),
 				},
 			},
 		},
@@ -195,6 +327,209 @@ func (r *SpringBootApplicationReconciler) createDeploymentObject(app *springv1al
 	return dep, nil
 }
 
+// createEnv combines the user provided environment variables with the ones the operator relies on
+func createEnv(app *springv1alpha1.SpringBootApplication, resources corev1.ResourceRequirements) ([]corev1.EnvVar, error) {
+	env := []corev1.EnvVar{}
+
+	for _, envVar := range app.Spec.Env {
+		if isReservedEnvVar(envVar.Name) || (envVar.Name == ENV_PROFILES_ACTIVE && len(app.Spec.Profiles) > 0) {
+			return nil, fmt.Errorf("environment variable %s is managed by the operator and cannot be set", envVar.Name)
+		}
+
+		env = append(env, envVar)
+	}
+
+	env = append(env, corev1.EnvVar{
+		// Using additional config means that this config is merged with their existing
+		// Configuration, meaning the config object doesn't have to be as large
+		Name:  ENV_CONFIG_LOCATION,
+		Value: strings.Join(configLocations(app), ","),
+	})
+
+	if len(app.Spec.Profiles) > 0 {
+		env = append(env, corev1.EnvVar{
+			Name:  ENV_PROFILES_ACTIVE,
+			Value: strings.Join(app.Spec.Profiles, ","),
+		})
+	}
+
+	// Native images are compiled ahead of time and don't run on the JVM
+	if app.Spec.Type == springv1alpha1.SpringNative {
+		return env, nil
+	}
+
+	return append(env, corev1.EnvVar{
+		Name:  ENV_JAVA_TOOL_OPTIONS,
+		Value: javaToolOptions(app, resources),
+	}), nil
+}
+
+func isReservedEnvVar(name string) bool {
+	return name == ENV_CONFIG_LOCATION || name == ENV_JAVA_TOOL_OPTIONS
+}
+
+// configLocations lists the additional spring config locations. Later locations take precedence,
+// so the generated application.yaml overrides shared config, and secrets are able to override both
+func configLocations(app *springv1alpha1.SpringBootApplication) []string {
+	locations := []string{}
+
+	// Shared config is loaded first, so the application's own config overrides it
+	for i, source := range app.Spec.ConfigFrom {
+		locations = append(locations, configFromLocation(i, source))
+	}
+
+	// Directories need a trailing slash, Spring then loads application.yaml and the profile
+	// specific files from them
+	locations = append(locations, CONFIG_MOUNT_PATH+"/")
+
+	for i, secret := range app.Spec.Secrets {
+		locations = append(locations, path.Join(secretMountPath(i), secretConfigKey(secret)))
+	}
+
+	return locations
+}
+
+// createVolumes creates the config volumes, the writable /tmp volume and any user defined volumes
+func createVolumes(app *springv1alpha1.SpringBootApplication) ([]corev1.Volume, []corev1.VolumeMount) {
+	volumes, mounts := createConfigVolumes(app)
+
+	// The root filesystem is read only, but tomcat, multipart uploads and plenty of libraries need to write to /tmp
+	tmp := &corev1.EmptyDirVolumeSource{
+		SizeLimit: ptr.To(tmpSizeLimit(app)),
+	}
+
+	if app.Spec.Tmp != nil && app.Spec.Tmp.Memory {
+		tmp.Medium = corev1.StorageMediumMemory
+	}
+
+	volumes = append(volumes, corev1.Volume{
+		Name: "tmp",
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: tmp,
+		},
+	})
+
+	mounts = append(mounts, corev1.VolumeMount{
+		Name:      "tmp",
+		MountPath: TMP_MOUNT_PATH,
+	})
+
+	for _, volume := range app.Spec.Volumes {
+		volumes = append(volumes, corev1.Volume{
+			Name: volume.Name,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir:              volume.EmptyDir,
+				PersistentVolumeClaim: volume.PersistentVolumeClaim,
+				ConfigMap:             volume.ConfigMap,
+				Secret:                volume.Secret,
+			},
+		})
+
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      volume.Name,
+			MountPath: volume.MountPath,
+			SubPath:   volume.SubPath,
+			ReadOnly:  volume.ReadOnly,
+		})
+	}
+
+	return volumes, mounts
+}
+
+// tmpSizeLimit is the size of /tmp, scaled with the resource preset unless set on the spec
+func tmpSizeLimit(app *springv1alpha1.SpringBootApplication) resource.Quantity {
+	if app.Spec.Tmp != nil && app.Spec.Tmp.SizeLimit != nil {
+		return *app.Spec.Tmp.SizeLimit
+	}
+
+	if app.Spec.ResourcePreset == nil {
+		return resource.MustParse("512Mi")
+	}
+
+	switch *app.Spec.ResourcePreset {
+	case springv1alpha1.Medium:
+		return resource.MustParse("512Mi")
+	case springv1alpha1.Large:
+		return resource.MustParse("1Gi")
+	default:
+		return resource.MustParse("256Mi")
+	}
+}
+
+// createConfigVolumes creates the volumes (and their mounts) for the generated configmap, any config secrets
+// and the shared config sources
+func createConfigVolumes(app *springv1alpha1.SpringBootApplication) ([]corev1.Volume, []corev1.VolumeMount) {
+	volumes := []corev1.Volume{
+		{
+			Name: "config",
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: app.Name,
+					},
+				},
+			},
+		},
+	}
+
+	mounts := []corev1.VolumeMount{
+		{
+			Name:      "config",
+			MountPath: CONFIG_MOUNT_PATH,
+		},
+	}
+
+	for i, secret := range app.Spec.Secrets {
+		// Secret names can be longer than volume names are allowed to be, so use the index
+		name := fmt.Sprintf("secret-%d", i)
+		key := secretConfigKey(secret)
+
+		volumes = append(volumes, corev1.Volume{
+			Name: name,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: secret.Name,
+					Items: []corev1.KeyToPath{
+						{
+							Key:  key,
+							Path: key,
+						},
+					},
+				},
+			},
+		})
+
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      name,
+			MountPath: secretMountPath(i),
+			ReadOnly:  true,
+		})
+	}
+
+	for i, source := range app.Spec.ConfigFrom {
+		volume, mount := createConfigFromVolume(i, source)
+
+		volumes = append(volumes, volume)
+		mounts = append(mounts, mount)
+	}
+
+	return volumes, mounts
+}
+
+// secretMountPath is the directory the secret's config file is mounted in. Secrets are mounted by
+// index, like the configFrom sources, so the paths never clash
+func secretMountPath(index int) string {
+	return path.Join(SECRETS_MOUNT_PATH, strconv.Itoa(index))
+}
+
+func secretConfigKey(secret springv1alpha1.SecretConfigSource) string {
+	if secret.Key == "" {
+		return "application.yaml"
+	}
+
+	return secret.Key
+}
+
 func createResources(app springv1alpha1.SpringBootApplication) (corev1.ResourceRequirements, error) {
 	if app.Spec.ResourcePreset == nil {
 		resources := app.Spec.Resources
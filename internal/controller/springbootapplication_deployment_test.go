@@ -135,19 +135,38 @@ var _ = Describe("Deployment Controller", func() {
 			volumeName := "config"
 			// Check volume is added
 			volumes := deploy.Spec.Template.Spec.Volumes
-			Expect(volumes).To(HaveLen(1))
+			Expect(volumes).To(HaveLen(2))
 			vol := volumes[0]
 			Expect(vol.Name).To(Equal(volumeName))
 			Expect(vol.ConfigMap.LocalObjectReference.Name).To(Equal(app.Name))
 
 			// Check it's mounted at /config
 			mounts := deploy.Spec.Template.Spec.Containers[0].VolumeMounts
-			Expect(mounts).To(HaveLen(1))
+			Expect(mounts).To(HaveLen(2))
 			configMount := mounts[0]
 			Expect(configMount.Name).To(Equal(volumeName))
 			Expect(configMount.MountPath).To(Equal("/config"))
 		})
 
+		It("mounts a writable emptyDir at /tmp sized for the preset", func() {
+			deploy := &appsv1.Deployment{}
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deploy)).To(Succeed())
+
+			volumes := deploy.Spec.Template.Spec.Volumes
+			tmp := volumes[1]
+			Expect(tmp.Name).To(Equal("tmp"))
+			Expect(tmp.EmptyDir).NotTo(BeNil())
+			Expect(tmp.EmptyDir.Medium).To(Equal(corev1.StorageMediumDefault))
+			Expect(*tmp.EmptyDir.SizeLimit).To(BeIdenticalTo(res.MustParse("256Mi")))
+
+			mounts := deploy.Spec.Template.Spec.Containers[0].VolumeMounts
+			Expect(mounts).To(ContainElement(corev1.VolumeMount{
+				Name:      "tmp",
+				MountPath: "/tmp",
+			}))
+		})
+
 		It("adds environment variable to tell where additional properties are located", func() {
 			deploy := &appsv1.Deployment{}
 
@@ -158,10 +177,10 @@ var _ = Describe("Deployment Controller", func() {
 			configEnvVar := env[0]
 
 			Expect(configEnvVar.Name).To(Equal("SPRING_CONFIG_ADDITIONAL_LOCATION"))
-			Expect(configEnvVar.Value).To(Equal("/config"))
+			Expect(configEnvVar.Value).To(Equal("/config/"))
 		})
 
-		It("uses 70 percent of available memory for the java heap", func() {
+		It("tunes the JVM for the preset", func() {
 			deploy := &appsv1.Deployment{}
 
 			Expect(k8sClient.Get(ctx, typeNamespacedName, deploy)).To(Succeed())
@@ -171,7 +190,158 @@ var _ = Describe("Deployment Controller", func() {
 			configEnvVar := env[1]
 
 			Expect(configEnvVar.Name).To(Equal("JAVA_TOOL_OPTIONS"))
-			Expect(configEnvVar.Value).To(Equal("-XX:MaxRAMPercentage=70"))
+			Expect(configEnvVar.Value).To(Equal("-XX:MaxRAMPercentage=65 -XX:+UseG1GC -XX:ActiveProcessorCount=2"))
+		})
+	})
+
+	Describe("with jvm settings", func() {
+		BeforeEach(func() {
+			app.Spec.ResourcePreset = ptr.To(springv1alpha1.Large)
+			app.Spec.JVM = &springv1alpha1.JVMConfig{
+				HeapPercentage: ptr.To(int32(80)),
+				GC:             springv1alpha1.ZGC,
+				HeapDumpPath:   "/tmp/heapdump.hprof",
+				ExtraFlags:     []string{"-XX:+ExitOnOutOfMemoryError"},
+			}
+			Expect(k8sClient.Update(ctx, app)).To(Succeed())
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("uses the settings from the spec", func() {
+			deploy := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deploy)).To(Succeed())
+
+			Expect(deploy.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{
+				Name:  "JAVA_TOOL_OPTIONS",
+				Value: "-XX:MaxRAMPercentage=80 -XX:+UseZGC -XX:ActiveProcessorCount=4 -XX:+HeapDumpOnOutOfMemoryError -XX:HeapDumpPath=/tmp/heapdump.hprof -XX:+ExitOnOutOfMemoryError",
+			}))
+		})
+	})
+
+	It("doesn't set JVM flags for native images", func() {
+		app.Spec.Type = springv1alpha1.SpringNative
+		Expect(k8sClient.Update(ctx, app)).To(Succeed())
+
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: typeNamespacedName,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		deploy := &appsv1.Deployment{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, deploy)).To(Succeed())
+
+		env := deploy.Spec.Template.Spec.Containers[0].Env
+		Expect(env).To(HaveLen(1))
+		Expect(env[0].Name).To(Equal("SPRING_CONFIG_ADDITIONAL_LOCATION"))
+	})
+
+	It("uses the rolling update defaults for the framework", func() {
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: typeNamespacedName,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		deploy := &appsv1.Deployment{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, deploy)).To(Succeed())
+
+		Expect(deploy.Spec.Strategy.RollingUpdate.MaxSurge).To(Equal(ptr.To(intstr.FromString("25%"))))
+		Expect(deploy.Spec.Strategy.RollingUpdate.MaxUnavailable).To(Equal(ptr.To(intstr.FromInt(0))))
+		Expect(deploy.Spec.MinReadySeconds).To(BeEquivalentTo(10))
+		Expect(deploy.Spec.ProgressDeadlineSeconds).To(Equal(ptr.To[int32](600)))
+	})
+
+	It("uses the rolling update settings from the spec", func() {
+		app.Spec.Type = springv1alpha1.SpringNative
+		app.Spec.Rollout = &springv1alpha1.RolloutConfig{
+			Strategy:        springv1alpha1.RollingUpdate,
+			MaxUnavailable:  ptr.To(intstr.FromString("10%")),
+			MinReadySeconds: ptr.To[int32](150),
+		}
+		Expect(k8sClient.Update(ctx, app)).To(Succeed())
+
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: typeNamespacedName,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		deploy := &appsv1.Deployment{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, deploy)).To(Succeed())
+
+		Expect(deploy.Spec.Strategy.RollingUpdate.MaxSurge).To(Equal(ptr.To(intstr.FromString("100%"))))
+		Expect(deploy.Spec.Strategy.RollingUpdate.MaxUnavailable).To(Equal(ptr.To(intstr.FromString("10%"))))
+		Expect(deploy.Spec.MinReadySeconds).To(BeEquivalentTo(150))
+
+		// The native default deadline is shorter than minReadySeconds, which Kubernetes doesn't allow
+		Expect(deploy.Spec.ProgressDeadlineSeconds).To(Equal(ptr.To[int32](270)))
+	})
+
+	Describe("with additional volumes", func() {
+		BeforeEach(func() {
+			app.Spec.Tmp = &springv1alpha1.TmpVolumeConfig{
+				SizeLimit: ptr.To(res.MustParse("2Gi")),
+				Memory:    true,
+			}
+			app.Spec.Volumes = []springv1alpha1.VolumeConfig{
+				{
+					Name:      "uploads",
+					MountPath: "/data/uploads",
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: "uploads",
+					},
+				},
+				{
+					Name:      "certs",
+					MountPath: "/etc/certs",
+					ReadOnly:  true,
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: "certs",
+					},
+				},
+			}
+
+			Expect(k8sClient.Update(ctx, app)).To(Succeed())
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("uses the tmp settings from the spec", func() {
+			deploy := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deploy)).To(Succeed())
+
+			tmp := deploy.Spec.Template.Spec.Volumes[1]
+			Expect(tmp.Name).To(Equal("tmp"))
+			Expect(tmp.EmptyDir.Medium).To(Equal(corev1.StorageMediumMemory))
+			Expect(*tmp.EmptyDir.SizeLimit).To(BeIdenticalTo(res.MustParse("2Gi")))
+		})
+
+		It("mounts the volumes into the container", func() {
+			deploy := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deploy)).To(Succeed())
+
+			volumes := deploy.Spec.Template.Spec.Volumes
+			Expect(volumes).To(HaveLen(4))
+			Expect(volumes[2].PersistentVolumeClaim.ClaimName).To(Equal("uploads"))
+			Expect(volumes[3].Secret.SecretName).To(Equal("certs"))
+
+			mounts := deploy.Spec.Template.Spec.Containers[0].VolumeMounts
+			Expect(mounts).To(ContainElement(corev1.VolumeMount{
+				Name:      "uploads",
+				MountPath: "/data/uploads",
+			}))
+			Expect(mounts).To(ContainElement(corev1.VolumeMount{
+				Name:      "certs",
+				MountPath: "/etc/certs",
+				ReadOnly:  true,
+			}))
 		})
 	})
 
@@ -203,6 +373,95 @@ var _ = Describe("Deployment Controller", func() {
 		})
 	})
 
+	Describe("graceful shutdown", func() {
+		It("waits for the preStop hook and spring's shutdown timeout by default", func() {
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			deploy := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deploy)).To(Succeed())
+
+			podSpec := deploy.Spec.Template.Spec
+
+			Expect(*podSpec.TerminationGracePeriodSeconds).To(BeEquivalentTo(40))
+			Expect(podSpec.Containers[0].Lifecycle.PreStop.Sleep.Seconds).To(BeEquivalentTo(5))
+		})
+
+		It("uses the shutdown settings from the spec", func() {
+			app.Spec.Shutdown = &springv1alpha1.ShutdownConfig{
+				TimeoutSeconds: ptr.To(int32(60)),
+				PreStopSeconds: ptr.To(int32(0)),
+			}
+			Expect(k8sClient.Update(ctx, app)).To(Succeed())
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			deploy := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deploy)).To(Succeed())
+
+			podSpec := deploy.Spec.Template.Spec
+
+			Expect(*podSpec.TerminationGracePeriodSeconds).To(BeEquivalentTo(65))
+			Expect(podSpec.Containers[0].Lifecycle).To(BeNil())
+		})
+	})
+
+	Describe("with a separate management port", func() {
+		BeforeEach(func() {
+			app.Spec.ContextPath = "/mypath"
+			app.Spec.Port = 8000
+			app.Spec.Management = &springv1alpha1.ManagementConfig{
+				Port:     9000,
+				BasePath: "/manage",
+			}
+
+			Expect(k8sClient.Update(ctx, app)).To(Succeed())
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("exposes the management port on the container", func() {
+			deploy := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deploy)).To(Succeed())
+
+			ports := deploy.Spec.Template.Spec.Containers[0].Ports
+
+			Expect(ports).To(HaveLen(2))
+			Expect(ports[1].Name).To(Equal("management"))
+			Expect(ports[1].ContainerPort).To(BeEquivalentTo(9000))
+		})
+
+		It("points the probes at the management port without the context path", func() {
+			deploy := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deploy)).To(Succeed())
+
+			container := deploy.Spec.Template.Spec.Containers[0]
+
+			Expect(container.ReadinessProbe.HTTPGet.Port).To(Equal(intstr.FromInt(9000)))
+			Expect(container.ReadinessProbe.HTTPGet.Path).To(Equal("/manage/health/readiness"))
+			Expect(container.LivenessProbe.HTTPGet.Port).To(Equal(intstr.FromInt(9000)))
+			Expect(container.LivenessProbe.HTTPGet.Path).To(Equal("/manage/health/liveness"))
+			Expect(container.StartupProbe.HTTPGet.Port).To(Equal(intstr.FromInt(9000)))
+		})
+
+		It("leaves the management port off the service", func() {
+			svc := &corev1.Service{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, svc)).To(Succeed())
+
+			Expect(svc.Spec.Ports).To(HaveLen(1))
+			Expect(svc.Spec.Ports[0].TargetPort).To(Equal(intstr.FromInt(8000)))
+		})
+	})
+
 	Describe("Readiness probes", func() {
 		It("should use context path and port when context path has no trailing slash", func() {
 			app.Spec.ContextPath = "/mypath"
@@ -392,6 +651,83 @@ var _ = Describe("Deployment Controller", func() {
 				FailureThreshold:    30, // Wait 5 minutes before declaring failure
 			}))
 		})
+
+		It("checks native images more often", func() {
+			app.Spec.Type = springv1alpha1.SpringNative
+			Expect(k8sClient.Update(ctx, app)).To(Succeed())
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			deploy := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deploy)).To(Succeed())
+
+			container := deploy.Spec.Template.Spec.Containers[0]
+
+			Expect(container.ReadinessProbe.PeriodSeconds).To(BeEquivalentTo(5))
+			Expect(container.StartupProbe.PeriodSeconds).To(BeEquivalentTo(1))
+			Expect(container.StartupProbe.FailureThreshold).To(BeEquivalentTo(30))
+		})
+
+		It("uses the probe settings from the spec", func() {
+			app.Spec.Port = 8000
+			app.Spec.Probes = &springv1alpha1.ProbesConfig{
+				Readiness: &springv1alpha1.ProbeConfig{
+					Type:          springv1alpha1.ProbeTCP,
+					PeriodSeconds: ptr.To[int32](2),
+				},
+				Liveness: &springv1alpha1.ProbeConfig{
+					Type:    springv1alpha1.ProbeExec,
+					Command: []string{"cat", "/tmp/healthy"},
+				},
+				Startup: &springv1alpha1.ProbeConfig{
+					Path: "/ping",
+				},
+			}
+			Expect(k8sClient.Update(ctx, app)).To(Succeed())
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			deploy := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deploy)).To(Succeed())
+
+			container := deploy.Spec.Template.Spec.Containers[0]
+
+			Expect(container.ReadinessProbe.TCPSocket.Port).To(Equal(intstr.FromInt(8000)))
+			Expect(container.ReadinessProbe.HTTPGet).To(BeNil())
+			Expect(container.ReadinessProbe.PeriodSeconds).To(BeEquivalentTo(2))
+			Expect(container.ReadinessProbe.FailureThreshold).To(BeEquivalentTo(3))
+
+			Expect(container.LivenessProbe.Exec.Command).To(Equal([]string{"cat", "/tmp/healthy"}))
+
+			Expect(container.StartupProbe.HTTPGet.Path).To(Equal("/ping"))
+			Expect(container.StartupProbe.FailureThreshold).To(BeEquivalentTo(30))
+		})
+
+		It("allows slow starting applications twice as long as they are expected to take", func() {
+			app.Spec.Probes = &springv1alpha1.ProbesConfig{
+				ExpectedStartupSeconds: ptr.To[int32](240),
+			}
+			Expect(k8sClient.Update(ctx, app)).To(Succeed())
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			deploy := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deploy)).To(Succeed())
+
+			startup := deploy.Spec.Template.Spec.Containers[0].StartupProbe
+
+			Expect(startup.PeriodSeconds).To(BeEquivalentTo(10))
+			Expect(startup.FailureThreshold).To(BeEquivalentTo(48))
+		})
 	})
 
 })
@@ -0,0 +1,224 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	springv1alpha1 "github.com/dante-lor/spring-boot-operator/api/v1alpha1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// The Gateway API types are not part of client-go, so HTTPRoutes are handled as unstructured objects
+var httpRouteGVK = schema.GroupVersionKind{
+	Group:   "gateway.networking.k8s.io",
+	Version: "v1",
+	Kind:    "HTTPRoute",
+}
+
+func newHTTPRoute(app *springv1alpha1.SpringBootApplication) *unstructured.Unstructured {
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(httpRouteGVK)
+	route.SetName(app.Name)
+	route.SetNamespace(app.Namespace)
+	return route
+}
+
+// Creates an Ingress or HTTPRoute for the application depending on spec.expose and removes
+// whichever of the two is no longer wanted
+func (r *SpringBootApplicationReconciler) ensureExposure(ctx context.Context, app *springv1alpha1.SpringBootApplication) error {
+	expose := app.Spec.Expose
+
+	wantIngress := expose != nil && expose.Mode != springv1alpha1.ExposeGateway
+	wantRoute := expose != nil && expose.Mode == springv1alpha1.ExposeGateway
+
+	if wantIngress {
+		if err := r.ensureIngress(ctx, app); err != nil {
+			return err
+		}
+	} else if err := r.deleteOwned(ctx, app, &networkingv1.Ingress{}); err != nil {
+		return err
+	}
+
+	if wantRoute {
+		return r.ensureHTTPRoute(ctx, app)
+	}
+
+	err := r.deleteOwned(ctx, app, newHTTPRoute(app))
+
+	// If the Gateway API isn't installed there can't be a route to clean up
+	if meta.IsNoMatchError(err) {
+		return nil
+	}
+
+	return err
+}
+
+func (r *SpringBootApplicationReconciler) ensureIngress(ctx context.Context, app *springv1alpha1.SpringBootApplication) error {
+	expose := app.Spec.Expose
+
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      app.Name,
+			Namespace: app.Namespace,
+		},
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, ingress, func() error {
+		ingress.Labels = app.Labels
+
+		pathType := networkingv1.PathTypePrefix
+
+		ingress.Spec = networkingv1.IngressSpec{
+			IngressClassName: expose.IngressClassName,
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: expose.Host,
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{
+									Path:     exposedPath(app.Spec.ContextPath),
+									PathType: &pathType,
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: app.Name,
+											Port: networkingv1.ServiceBackendPort{
+												Name: "http",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		if expose.TLS != nil {
+			ingress.Spec.TLS = []networkingv1.IngressTLS{
+				{
+					Hosts:      []string{expose.Host},
+					SecretName: expose.TLS.SecretName,
+				},
+			}
+		}
+
+		return controllerutil.SetControllerReference(app, ingress, r.Scheme)
+	})
+
+	return err
+}
+
+func (r *SpringBootApplicationReconciler) ensureHTTPRoute(ctx context.Context, app *springv1alpha1.SpringBootApplication) error {
+	expose := app.Spec.Expose
+
+	if expose.ParentRef == nil {
+		return fmt.Errorf("expose.parentRef must be set when exposing through a gateway")
+	}
+
+	parentRef := map[string]interface{}{
+		"name": expose.ParentRef.Name,
+	}
+
+	if expose.ParentRef.Namespace != "" {
+		parentRef["namespace"] = expose.ParentRef.Namespace
+	}
+
+	if expose.ParentRef.SectionName != "" {
+		parentRef["sectionName"] = expose.ParentRef.SectionName
+	}
+
+	route := newHTTPRoute(app)
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, route, func() error {
+		route.SetLabels(app.Labels)
+
+		route.Object["spec"] = map[string]interface{}{
+			"parentRefs": []interface{}{parentRef},
+			"hostnames":  []interface{}{expose.Host},
+			"rules": []interface{}{
+				map[string]interface{}{
+					"matches": []interface{}{
+						map[string]interface{}{
+							"path": map[string]interface{}{
+								"type":  "PathPrefix",
+								"value": exposedPath(app.Spec.ContextPath),
+							},
+						},
+					},
+					"backendRefs": []interface{}{
+						map[string]interface{}{
+							"name": app.Name,
+							"port": int64(EXTERNAL_PORT),
+						},
+					},
+				},
+			},
+		}
+
+		return controllerutil.SetControllerReference(app, route, r.Scheme)
+	})
+
+	if meta.IsNoMatchError(err) {
+		return fmt.Errorf("cannot expose through a gateway, the Gateway API is not installed: %w", err)
+	}
+
+	return err
+}
+
+// Deletes the object if it exists and is controlled by the application. The object is looked up by its
+// name, or the application's name if it doesn't have one
+func (r *SpringBootApplicationReconciler) deleteOwned(ctx context.Context, app *springv1alpha1.SpringBootApplication, obj client.Object) error {
+	key := client.ObjectKeyFromObject(app)
+
+	if obj.GetName() != "" {
+		key.Name = obj.GetName()
+	}
+
+	err := r.Get(ctx, key, obj)
+
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if !metav1.IsControlledBy(obj, app) {
+		return nil
+	}
+
+	return client.IgnoreNotFound(r.Delete(ctx, obj))
+}
+
+// exposedURL works out the URL the application will be reachable on, or an empty string if it isn't exposed
+func exposedURL(app *springv1alpha1.SpringBootApplication) string {
+	expose := app.Spec.Expose
+
+	if expose == nil {
+		return ""
+	}
+
+	scheme := "http"
+
+	if expose.TLS != nil {
+		scheme = "https"
+	}
+
+	return fmt.Sprintf("%s://%s%s", scheme, expose.Host, exposedPath(app.Spec.ContextPath))
+}
+
+// exposedPath turns the context path into a path prefix usable by ingresses and routes
+func exposedPath(contextPath string) string {
+	return "/" + strings.Trim(contextPath, "/")
+}
@@ -7,13 +7,88 @@ import (
 	springv1alpha1 "github.com/dante-lor/spring-boot-operator/api/v1alpha1"
 	scalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
+// Creates the HPA or KEDA ScaledObject for the application, removing the one which isn't used. Both
+// are removed when autoscaling is disabled
 func (r *SpringBootApplicationReconciler) ensureAutoscaler(ctx context.Context, app *springv1alpha1.SpringBootApplication) error {
+	if !autoscalingEnabled(app) {
+		if err := r.deleteOwned(ctx, app, &scalingv2.HorizontalPodAutoscaler{}); err != nil {
+			return err
+		}
+
+		err := r.deleteOwned(ctx, app, newScaledObject(app))
+
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	if app.Spec.Autoscaler.Engine == springv1alpha1.EngineKeda {
+		if err := r.deleteOwned(ctx, app, &scalingv2.HorizontalPodAutoscaler{}); err != nil {
+			return err
+		}
+
+		return r.ensureScaledObject(ctx, app)
+	}
+
+	err := r.deleteOwned(ctx, app, newScaledObject(app))
+
+	// If KEDA isn't installed there can't be a ScaledObject to clean up
+	if err != nil && !meta.IsNoMatchError(err) {
+		return err
+	}
+
+	return r.ensureHPA(ctx, app)
+}
+
+func autoscalingEnabled(app *springv1alpha1.SpringBootApplication) bool {
+	return ptr.Deref(app.Spec.Autoscaler.Enabled, true)
+}
+
+// fixedReplicas is the replica count used when autoscaling is disabled
+func fixedReplicas(app *springv1alpha1.SpringBootApplication) int32 {
+	return ptr.Deref(app.Spec.Replicas, 1)
+}
+
+// minReplicas is the lowest number of replicas the application can be scaled down to. When autoscaling,
+// spec.replicas (set by kubectl scale) overrides the active schedule, which overrides the autoscaler's min replicas
+func minReplicas(app *springv1alpha1.SpringBootApplication) int32 {
+	if !autoscalingEnabled(app) {
+		return fixedReplicas(app)
+	}
+
+	if app.Spec.Replicas != nil {
+		return *app.Spec.Replicas
+	}
+
+	if schedule := activeSchedule(app); schedule != nil && schedule.MinReplicas != nil {
+		return *schedule.MinReplicas
+	}
+
+	return int32(app.Spec.Autoscaler.MinReplicas)
+}
+
+// maxReplicas is the autoscaler's (or active schedule's) max replicas, raised if needed so it's never below
+// the min replicas
+func maxReplicas(app *springv1alpha1.SpringBootApplication) int32 {
+	maxReplicas := int32(app.Spec.Autoscaler.MaxReplicas)
+
+	if schedule := activeSchedule(app); schedule != nil && schedule.MaxReplicas != nil {
+		maxReplicas = *schedule.MaxReplicas
+	}
+
+	return max(maxReplicas, minReplicas(app))
+}
+
+func (r *SpringBootApplicationReconciler) ensureHPA(ctx context.Context, app *springv1alpha1.SpringBootApplication) error {
 
 	// Get existing configmap
 	existing := &scalingv2.HorizontalPodAutoscaler{}
@@ -55,42 +130,34 @@ func createAutoscalerSpec(app *springv1alpha1.SpringBootApplication) (scalingv2.
 	spec.ScaleTargetRef = scalingv2.CrossVersionObjectReference{
 		Kind:       "Deployment",
 		APIVersion: "apps/v1",
-		Name:       app.Name,
+		Name:       activeDeploymentName(app),
 	}
 
 	// Replicas
-	spec.MinReplicas = ptr.To(int32(config.MinReplicas))
-	spec.MaxReplicas = int32(config.MaxReplicas)
-
-	// CPU utilization
-	averageTargetPercent := config.TargetUtilization.CpuPercentage
-
-	if averageTargetPercent == nil {
-		switch app.Spec.Type {
-		case springv1alpha1.SpringWeb:
-			averageTargetPercent = ptr.To(int32(70))
-		case springv1alpha1.SpringWebflux:
-			averageTargetPercent = ptr.To(int32(75))
-		case springv1alpha1.SpringNative:
-			averageTargetPercent = ptr.To(int32(65))
-		default:
-			return spec, fmt.Errorf("unrecognized application type: %s", app.Spec.Type)
-		}
+	// An HPA can't scale to zero
+	spec.MinReplicas = ptr.To(max(minReplicas(app), 1))
+	spec.MaxReplicas = max(maxReplicas(app), 1)
+
+	// Resource utilization
+	defaultCPU, defaultMemory, err := getDefaultUtilization(app.Spec.Type)
+
+	if err != nil {
+		return spec, err
 	}
 
+	target := config.TargetUtilization
+
 	spec.Metrics = []scalingv2.MetricSpec{
-		{
-			Type: scalingv2.ResourceMetricSourceType,
-			Resource: &scalingv2.ResourceMetricSource{
-				Name: corev1.ResourceCPU,
-				Target: scalingv2.MetricTarget{
-					Type:               scalingv2.UtilizationMetricType,
-					AverageUtilization: averageTargetPercent,
-				},
-			},
-		},
+		createUtilizationMetric(corev1.ResourceCPU, ptr.Deref(target.CpuPercentage, defaultCPU)),
 	}
 
+	if target.Memory || target.MemoryPercentage != nil {
+		spec.Metrics = append(spec.Metrics, createUtilizationMetric(corev1.ResourceMemory, ptr.Deref(target.MemoryPercentage, defaultMemory)))
+	}
+
+	// Custom metrics
+	spec.Metrics = append(spec.Metrics, config.Metrics...)
+
 	// Scaling Behavior
 	defaultBehaviour, err := getDefaultBehaviour(app.Spec.Type)
 
@@ -109,6 +176,34 @@ func createAutoscalerSpec(app *springv1alpha1.SpringBootApplication) (scalingv2.
 	return spec, nil
 }
 
+func createUtilizationMetric(name corev1.ResourceName, averageUtilization int32) scalingv2.MetricSpec {
+	return scalingv2.MetricSpec{
+		Type: scalingv2.ResourceMetricSourceType,
+		Resource: &scalingv2.ResourceMetricSource{
+			Name: name,
+			Target: scalingv2.MetricTarget{
+				Type:               scalingv2.UtilizationMetricType,
+				AverageUtilization: ptr.To(averageUtilization),
+			},
+		},
+	}
+}
+
+// getDefaultUtilization returns the cpu and memory percentage targets for the framework. Memory targets are
+// high since the JVM holds on to its heap, so memory usage only drops slowly after load goes away
+func getDefaultUtilization(springFramework springv1alpha1.SpringFramework) (int32, int32, error) {
+	switch springFramework {
+	case springv1alpha1.SpringWeb:
+		return 70, 80, nil
+	case springv1alpha1.SpringWebflux:
+		return 75, 80, nil
+	case springv1alpha1.SpringNative:
+		return 65, 75, nil
+	}
+
+	return 0, 0, fmt.Errorf("unrecognized application type: %s", springFramework)
+}
+
 func mergeBehaviours(custom *scalingv2.HorizontalPodAutoscalerBehavior, defaultBehaviour *scalingv2.HorizontalPodAutoscalerBehavior) *scalingv2.HorizontalPodAutoscalerBehavior {
 
 	if custom.ScaleUp == nil {
@@ -6,7 +6,12 @@ import (
 	springv1alpha1 "github.com/dante-lor/spring-boot-operator/api/v1alpha1"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
 	scalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
@@ -95,4 +100,213 @@ var _ = Describe("HPA Controller", func() {
 		Expect(*hpa.Spec.Behavior.ScaleUp.StabilizationWindowSeconds).To(Equal(int32(60)))
 		Expect(*hpa.Spec.Behavior.ScaleDown.StabilizationWindowSeconds).To(Equal(int32(300)))
 	})
+
+	It("should add a memory target with the default for the framework", func() {
+		app.Spec.Autoscaler.TargetUtilization.Memory = true
+		Expect(k8sClient.Update(ctx, app)).To(Succeed())
+
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: typeNamespacedName,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		hpa := &scalingv2.HorizontalPodAutoscaler{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, hpa)).To(Succeed())
+
+		Expect(hpa.Spec.Metrics).To(HaveLen(2))
+		Expect(hpa.Spec.Metrics[1].Resource.Name).To(Equal(corev1.ResourceMemory))
+		Expect(*hpa.Spec.Metrics[1].Resource.Target.AverageUtilization).To(Equal(int32(80)))
+	})
+
+	It("should use memory and custom metrics alongside cpu", func() {
+		app.Spec.Autoscaler.TargetUtilization.MemoryPercentage = ptr.To(int32(90))
+		app.Spec.Autoscaler.Metrics = []scalingv2.MetricSpec{
+			{
+				Type: scalingv2.PodsMetricSourceType,
+				Pods: &scalingv2.PodsMetricSource{
+					Metric: scalingv2.MetricIdentifier{
+						Name: "http_server_requests_per_second",
+					},
+					Target: scalingv2.MetricTarget{
+						Type:         scalingv2.AverageValueMetricType,
+						AverageValue: ptr.To(resource.MustParse("100")),
+					},
+				},
+			},
+		}
+		Expect(k8sClient.Update(ctx, app)).To(Succeed())
+
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: typeNamespacedName,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		hpa := &scalingv2.HorizontalPodAutoscaler{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, hpa)).To(Succeed())
+
+		Expect(hpa.Spec.Metrics).To(HaveLen(3))
+		Expect(hpa.Spec.Metrics[0].Resource.Name).To(Equal(corev1.ResourceCPU))
+		Expect(*hpa.Spec.Metrics[1].Resource.Target.AverageUtilization).To(Equal(int32(90)))
+		Expect(hpa.Spec.Metrics[2].Pods.Metric.Name).To(Equal("http_server_requests_per_second"))
+	})
+
+	It("should fail to use the keda engine when KEDA isn't installed", func() {
+		app.Spec.Autoscaler.Engine = springv1alpha1.EngineKeda
+		app.Spec.Autoscaler.Keda = &springv1alpha1.KedaConfig{
+			Triggers: []springv1alpha1.KedaTrigger{
+				{
+					Type: "kafka",
+					Metadata: map[string]string{
+						"topic":         "orders",
+						"consumerGroup": "orders",
+						"lagThreshold":  "50",
+					},
+				},
+			},
+			ScaleToZero: true,
+		}
+		Expect(k8sClient.Update(ctx, app)).To(Succeed())
+
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: typeNamespacedName,
+		})
+		Expect(err).To(MatchError(ContainSubstring("KEDA is not installed")))
+	})
+
+	It("should create a ScaledObject spec from the keda triggers", func() {
+		app.Spec.Autoscaler.Engine = springv1alpha1.EngineKeda
+		app.Spec.Autoscaler.Keda = &springv1alpha1.KedaConfig{
+			Triggers: []springv1alpha1.KedaTrigger{
+				{
+					Type: "rabbitmq",
+					Metadata: map[string]string{
+						"queueName": "orders",
+						"value":     "20",
+					},
+					AuthenticationRef: "rabbitmq-auth",
+				},
+			},
+			CooldownPeriod: ptr.To(int32(120)),
+			ScaleToZero:    true,
+		}
+
+		spec, err := createScaledObjectSpec(app)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(spec).To(HaveKeyWithValue("minReplicaCount", int64(1)))
+		Expect(spec).To(HaveKeyWithValue("maxReplicaCount", int64(5)))
+		Expect(spec).To(HaveKeyWithValue("idleReplicaCount", int64(0)))
+		Expect(spec).To(HaveKeyWithValue("cooldownPeriod", int64(120)))
+		Expect(spec).NotTo(HaveKey("pollingInterval"))
+
+		triggers := spec["triggers"].([]interface{})
+		Expect(triggers).To(HaveLen(1))
+		Expect(triggers[0]).To(HaveKeyWithValue("type", "rabbitmq"))
+		Expect(triggers[0]).To(HaveKeyWithValue("authenticationRef", map[string]interface{}{"name": "rabbitmq-auth"}))
+	})
+
+	It("should not report an idle app scaled to zero by KEDA as degraded", func() {
+		deploy := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Generation: 1},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To(int32(0)),
+			},
+			Status: appsv1.DeploymentStatus{ObservedGeneration: 1},
+		}
+
+		hpa := &scalingv2.HorizontalPodAutoscaler{
+			Status: scalingv2.HorizontalPodAutoscalerStatus{
+				Conditions: []scalingv2.HorizontalPodAutoscalerCondition{
+					{
+						Type:    scalingv2.ScalingActive,
+						Status:  corev1.ConditionFalse,
+						Reason:  "ScalingDisabled",
+						Message: "scaling is disabled since the replica count of the target is zero",
+					},
+				},
+			},
+		}
+
+		conditions := computeConditions(deploy, hpa)
+		Expect(meta.IsStatusConditionFalse(conditions, "Degraded")).To(BeTrue())
+
+		// Other reasons for the autoscaler being inactive are still reported
+		hpa.Status.Conditions[0].Reason = "FailedGetExternalMetric"
+
+		conditions = computeConditions(deploy, hpa)
+		Expect(meta.IsStatusConditionTrue(conditions, "Degraded")).To(BeTrue())
+		Expect(meta.FindStatusCondition(conditions, "Degraded").Reason).To(Equal("AutoscalingInactive"))
+	})
+
+	It("should run a fixed number of replicas when autoscaling is disabled", func() {
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: typeNamespacedName,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(k8sClient.Get(ctx, typeNamespacedName, &scalingv2.HorizontalPodAutoscaler{})).To(Succeed())
+
+		// Reconciling updated the status, so fetch the latest version before changing it
+		Expect(k8sClient.Get(ctx, typeNamespacedName, app)).To(Succeed())
+		app.Spec.Autoscaler.Enabled = ptr.To(false)
+		app.Spec.Replicas = ptr.To(int32(3))
+		Expect(k8sClient.Update(ctx, app)).To(Succeed())
+
+		_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: typeNamespacedName,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		err = k8sClient.Get(ctx, typeNamespacedName, &scalingv2.HorizontalPodAutoscaler{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+		deploy := &appsv1.Deployment{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, deploy)).To(Succeed())
+		Expect(*deploy.Spec.Replicas).To(Equal(int32(3)))
+	})
+
+	It("should keep the replicas set by the autoscaler when updating the deployment", func() {
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: typeNamespacedName,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		deploy := &appsv1.Deployment{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, deploy)).To(Succeed())
+
+		// Pretend the autoscaler has scaled up
+		deploy.Spec.Replicas = ptr.To(int32(4))
+		Expect(k8sClient.Update(ctx, deploy)).To(Succeed())
+
+		// Reconciling updated the status, so fetch the latest version before changing it
+		Expect(k8sClient.Get(ctx, typeNamespacedName, app)).To(Succeed())
+		app.Spec.Image = "test:2"
+		Expect(k8sClient.Update(ctx, app)).To(Succeed())
+
+		_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: typeNamespacedName,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(k8sClient.Get(ctx, typeNamespacedName, deploy)).To(Succeed())
+		Expect(*deploy.Spec.Replicas).To(Equal(int32(4)))
+		Expect(deploy.Spec.Template.Spec.Containers[0].Image).To(Equal("test:2"))
+	})
+
+	It("should use spec.replicas as the min replicas when autoscaling", func() {
+		app.Spec.Replicas = ptr.To(int32(8))
+		Expect(k8sClient.Update(ctx, app)).To(Succeed())
+
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: typeNamespacedName,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		hpa := &scalingv2.HorizontalPodAutoscaler{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, hpa)).To(Succeed())
+
+		Expect(*hpa.Spec.MinReplicas).To(Equal(int32(8)))
+		// Raised so the autoscaler stays valid
+		Expect(hpa.Spec.MaxReplicas).To(Equal(int32(8)))
+	})
 })
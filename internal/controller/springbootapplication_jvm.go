@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	springv1alpha1 "github.com/dante-lor/spring-boot-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// javaToolOptions builds the JVM flags for the application. The defaults depend on the resources the
+// container gets, so they are worked out from the same requirements as the container resources
+func javaToolOptions(app *springv1alpha1.SpringBootApplication, resources corev1.ResourceRequirements) string {
+	jvm := app.Spec.JVM
+
+	if jvm == nil {
+		jvm = &springv1alpha1.JVMConfig{}
+	}
+
+	heapPercentage := defaultHeapPercentage(app.Spec.ResourcePreset)
+
+	if jvm.HeapPercentage != nil {
+		heapPercentage = *jvm.HeapPercentage
+	}
+
+	gc := springv1alpha1.G1GC
+
+	if jvm.GC != "" {
+		gc = jvm.GC
+	}
+
+	processors := defaultActiveProcessorCount(resources)
+
+	if jvm.ActiveProcessorCount != nil {
+		processors = *jvm.ActiveProcessorCount
+	}
+
+	flags := []string{
+		fmt.Sprintf("-XX:MaxRAMPercentage=%d", heapPercentage),
+		gcFlag(gc),
+		fmt.Sprintf("-XX:ActiveProcessorCount=%d", processors),
+	}
+
+	if jvm.HeapDumpPath != "" {
+		flags = append(flags, "-XX:+HeapDumpOnOutOfMemoryError", "-XX:HeapDumpPath="+jvm.HeapDumpPath)
+	}
+
+	flags = append(flags, jvm.ExtraFlags...)
+
+	return strings.Join(flags, " ")
+}
+
+// By default, java only uses 25% of it's memory for the java heap, which is quite low. Some needs to be
+// left for metaspace, thread stacks and the GC, which is a bigger share of a small container.
+func defaultHeapPercentage(preset *springv1alpha1.ResourcePreset) int32 {
+	if preset == nil {
+		return 70
+	}
+
+	switch *preset {
+	case springv1alpha1.Small:
+		return 65
+	case springv1alpha1.Large:
+		return 75
+	default:
+		return 70
+	}
+}
+
+// There's no CPU limit, so the JVM would size its thread pools for every CPU on the node. The CPU request
+// is used instead, with at least 2 so the JVM doesn't fall back to the serial collector.
+func defaultActiveProcessorCount(resources corev1.ResourceRequirements) int32 {
+	cpu := resources.Requests.Cpu().MilliValue()
+	processors := int32((cpu + 999) / 1000)
+
+	return max(processors, 2)
+}
+
+func gcFlag(gc springv1alpha1.GarbageCollector) string {
+	switch gc {
+	case springv1alpha1.ParallelGC:
+		return "-XX:+UseParallelGC"
+	case springv1alpha1.SerialGC:
+		return "-XX:+UseSerialGC"
+	case springv1alpha1.ZGC:
+		return "-XX:+UseZGC"
+	default:
+		return "-XX:+UseG1GC"
+	}
+}
@@ -0,0 +1,149 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	springv1alpha1 "github.com/dante-lor/spring-boot-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// The KEDA types are not part of client-go, so ScaledObjects are handled as unstructured objects
+var scaledObjectGVK = schema.GroupVersionKind{
+	Group:   "keda.sh",
+	Version: "v1alpha1",
+	Kind:    "ScaledObject",
+}
+
+func newScaledObject(app *springv1alpha1.SpringBootApplication) *unstructured.Unstructured {
+	scaledObject := &unstructured.Unstructured{}
+	scaledObject.SetGroupVersionKind(scaledObjectGVK)
+	scaledObject.SetName(app.Name)
+	scaledObject.SetNamespace(app.Namespace)
+	return scaledObject
+}
+
+// hpaName is the name of the HorizontalPodAutoscaler scaling the application. KEDA creates its own HPA
+// for each ScaledObject
+func hpaName(app *springv1alpha1.SpringBootApplication) string {
+	if app.Spec.Autoscaler.Engine == springv1alpha1.EngineKeda {
+		return "keda-hpa-" + app.Name
+	}
+
+	return app.Name
+}
+
+func (r *SpringBootApplicationReconciler) ensureScaledObject(ctx context.Context, app *springv1alpha1.SpringBootApplication) error {
+	if app.Spec.Autoscaler.Keda == nil || len(app.Spec.Autoscaler.Keda.Triggers) == 0 {
+		return fmt.Errorf("autoscaler.keda.triggers must be set when using the keda engine")
+	}
+
+	spec, err := createScaledObjectSpec(app)
+
+	if err != nil {
+		return err
+	}
+
+	scaledObject := newScaledObject(app)
+
+	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, scaledObject, func() error {
+		scaledObject.SetLabels(app.Labels)
+		scaledObject.Object["spec"] = spec
+
+		return controllerutil.SetControllerReference(app, scaledObject, r.Scheme)
+	})
+
+	if meta.IsNoMatchError(err) {
+		return fmt.Errorf("cannot use the keda autoscaler engine, KEDA is not installed: %w", err)
+	}
+
+	return err
+}
+
+func createScaledObjectSpec(app *springv1alpha1.SpringBootApplication) (map[string]interface{}, error) {
+	config := app.Spec.Autoscaler
+	keda := config.Keda
+
+	triggers := []interface{}{}
+
+	for _, trigger := range keda.Triggers {
+		metadata := map[string]interface{}{}
+
+		for key, value := range trigger.Metadata {
+			metadata[key] = value
+		}
+
+		kedaTrigger := map[string]interface{}{
+			"type":     trigger.Type,
+			"metadata": metadata,
+		}
+
+		if trigger.Name != "" {
+			kedaTrigger["name"] = trigger.Name
+		}
+
+		if trigger.AuthenticationRef != "" {
+			kedaTrigger["authenticationRef"] = map[string]interface{}{
+				"name": trigger.AuthenticationRef,
+			}
+		}
+
+		if trigger.MetricType != "" {
+			kedaTrigger["metricType"] = string(trigger.MetricType)
+		}
+
+		triggers = append(triggers, kedaTrigger)
+	}
+
+	// KEDA scales through an HPA of its own, so it uses the same scaling behaviour as the hpa engine
+	behaviour, err := getDefaultBehaviour(app.Spec.Type)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Behaviour != nil {
+		behaviour = mergeBehaviours(config.Behaviour.DeepCopy(), behaviour)
+	}
+
+	unstructuredBehaviour, err := runtime.DefaultUnstructuredConverter.ToUnstructured(behaviour)
+
+	if err != nil {
+		return nil, err
+	}
+
+	spec := map[string]interface{}{
+		"scaleTargetRef": map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"name":       activeDeploymentName(app),
+		},
+		"minReplicaCount": int64(minReplicas(app)),
+		"maxReplicaCount": int64(max(maxReplicas(app), 1)),
+		"advanced": map[string]interface{}{
+			"horizontalPodAutoscalerConfig": map[string]interface{}{
+				"behavior": unstructuredBehaviour,
+			},
+		},
+		"triggers": triggers,
+	}
+
+	if keda.PollingInterval != nil {
+		spec["pollingInterval"] = int64(*keda.PollingInterval)
+	}
+
+	if keda.CooldownPeriod != nil {
+		spec["cooldownPeriod"] = int64(*keda.CooldownPeriod)
+	}
+
+	// KEDA only supports an idle replica count of zero, which must be below the min replicas
+	if keda.ScaleToZero && minReplicas(app) > 0 {
+		spec["idleReplicaCount"] = int64(0)
+	}
+
+	return spec, nil
+}
@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	springv1alpha1 "github.com/dante-lor/spring-boot-operator/api/v1alpha1"
+)
+
+const MANAGEMENT_PORT_NAME = "management"
+
+// managementPort is the port actuator is served on
+func managementPort(app *springv1alpha1.SpringBootApplication) int {
+	if hasManagementPort(app) {
+		return app.Spec.Management.Port
+	}
+
+	return app.Spec.Port
+}
+
+// hasManagementPort is true when actuator is served on its own port rather than the application port
+func hasManagementPort(app *springv1alpha1.SpringBootApplication) bool {
+	management := app.Spec.Management
+
+	return management != nil && management.Port != 0 && management.Port != app.Spec.Port
+}
+
+// actuatorPath is the base path of the spring boot actuator endpoints. Spring doesn't apply the
+// context path to actuator when it runs on a separate port
+func actuatorPath(app *springv1alpha1.SpringBootApplication) string {
+	basePath := "/actuator"
+
+	if app.Spec.Management != nil && app.Spec.Management.BasePath != "" {
+		basePath = app.Spec.Management.BasePath
+	}
+
+	prefix := app.Spec.ContextPath
+
+	if hasManagementPort(app) {
+		prefix = ""
+	}
+
+	return strings.TrimSuffix(prefix, "/") + strings.TrimSuffix(basePath, "/")
+}
+
+// mergeManagementConfig sets the management properties from spec.management on the config
+func mergeManagementConfig(config map[string]interface{}, management *springv1alpha1.ManagementConfig) error {
+	if management == nil {
+		return nil
+	}
+
+	if management.Port != 0 {
+		nestedMap(config, "management", "server")["port"] = management.Port
+	}
+
+	if management.BasePath != "" {
+		nestedMap(config, "management", "endpoints", "web")["base-path"] = management.BasePath
+	}
+
+	if len(management.Endpoints) > 0 {
+		nestedMap(config, "management", "endpoints", "web", "exposure")["include"] = strings.Join(management.Endpoints, ",")
+
+		return exposeActuatorEndpoint(config, "health")
+	}
+
+	return nil
+}
+
+// nestedMap gets the map at the given path in the config, creating any maps which are missing
+func nestedMap(config map[string]interface{}, keys ...string) map[string]interface{} {
+	current := config
+
+	for _, key := range keys {
+		next, ok := current[key].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			current[key] = next
+		}
+
+		current = next
+	}
+
+	return current
+}
+
+// exposeActuatorEndpoint adds the endpoint to management.endpoints.web.exposure.include. When nothing
+// is configured, health is kept alongside it since the probes rely on it
+func exposeActuatorEndpoint(config map[string]interface{}, endpoint string) error {
+	management, ok := config["management"].(map[string]interface{})
+	if !ok {
+		management = map[string]interface{}{}
+	}
+
+	endpoints, ok := management["endpoints"].(map[string]interface{})
+	if !ok {
+		endpoints = map[string]interface{}{}
+	}
+
+	web, ok := endpoints["web"].(map[string]interface{})
+	if !ok {
+		web = map[string]interface{}{}
+	}
+
+	exposure, ok := web["exposure"].(map[string]interface{})
+	if !ok {
+		exposure = map[string]interface{}{}
+	}
+
+	var include []string
+
+	switch value := exposure["include"].(type) {
+	case nil:
+		include = []string{"health"}
+	case string:
+		include = strings.Split(value, ",")
+	case []interface{}:
+		for _, item := range value {
+			include = append(include, fmt.Sprint(item))
+		}
+	default:
+		return fmt.Errorf("management.endpoints.web.exposure.include must be a string or list")
+	}
+
+	for _, item := range include {
+		if item = strings.TrimSpace(item); item == endpoint || item == "*" {
+			return nil
+		}
+	}
+
+	exposure["include"] = strings.Join(append(include, endpoint), ",")
+
+	web["exposure"] = exposure
+	endpoints["web"] = web
+	management["endpoints"] = endpoints
+	config["management"] = management
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package controller
+
+import (
+	"context"
+	"strconv"
+
+	springv1alpha1 "github.com/dante-lor/spring-boot-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	METRICS_PORT      = 9090
+	METRICS_PORT_NAME = "metrics"
+	METRICS_LABEL     = "spring.dante-lor.github.io/metrics"
+)
+
+// The Prometheus Operator types are not part of client-go, so ServiceMonitors are handled as unstructured objects
+var serviceMonitorGVK = schema.GroupVersionKind{
+	Group:   "monitoring.coreos.com",
+	Version: "v1",
+	Kind:    "ServiceMonitor",
+}
+
+func newServiceMonitor(app *springv1alpha1.SpringBootApplication) *unstructured.Unstructured {
+	monitor := &unstructured.Unstructured{}
+	monitor.SetGroupVersionKind(serviceMonitorGVK)
+	monitor.SetName(app.Name)
+	monitor.SetNamespace(app.Namespace)
+	return monitor
+}
+
+// serviceMonitorsAvailable checks if the Prometheus Operator CRDs are installed in the cluster
+func (r *SpringBootApplicationReconciler) serviceMonitorsAvailable() bool {
+	return apiAvailable(r.RESTMapper(), serviceMonitorGVK)
+}
+
+// Creates a ServiceMonitor for the application's metrics when they are enabled and the Prometheus
+// Operator is installed, otherwise removes it
+func (r *SpringBootApplicationReconciler) ensureServiceMonitor(ctx context.Context, app *springv1alpha1.SpringBootApplication) error {
+	if app.Spec.Metrics == nil || !r.serviceMonitorsAvailable() {
+		if err := r.deleteOwned(ctx, app, &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: metricsServiceName(app)}}); err != nil {
+			return err
+		}
+	}
+
+	if !r.serviceMonitorsAvailable() {
+		// Scrape annotations are used instead
+		return nil
+	}
+
+	if app.Spec.Metrics == nil {
+		err := r.deleteOwned(ctx, app, newServiceMonitor(app))
+
+		if meta.IsNoMatchError(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	if err := r.ensureMetricsService(ctx, app); err != nil {
+		return err
+	}
+
+	endpoint := map[string]interface{}{
+		"port": METRICS_PORT_NAME,
+		"path": metricsPath(app),
+	}
+
+	if app.Spec.Metrics.Interval != "" {
+		endpoint["interval"] = app.Spec.Metrics.Interval
+	}
+
+	monitor := newServiceMonitor(app)
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, monitor, func() error {
+		labels := map[string]string{}
+
+		for key, value := range app.Labels {
+			labels[key] = value
+		}
+
+		for key, value := range app.Spec.Metrics.Labels {
+			labels[key] = value
+		}
+
+		monitor.SetLabels(labels)
+
+		monitor.Object["spec"] = map[string]interface{}{
+			"selector": map[string]interface{}{
+				"matchLabels": map[string]interface{}{
+					"app":         app.Name,
+					METRICS_LABEL: "true",
+				},
+			},
+			"endpoints": []interface{}{endpoint},
+		}
+
+		return controllerutil.SetControllerReference(app, monitor, r.Scheme)
+	})
+
+	return err
+}
+
+func metricsServiceName(app *springv1alpha1.SpringBootApplication) string {
+	return app.Name + "-metrics"
+}
+
+// ensureMetricsService creates a headless service for the ServiceMonitor to find the pods through, so actuator
+// isn't added to the service which ingresses and routes send traffic to
+func (r *SpringBootApplicationReconciler) ensureMetricsService(ctx context.Context, app *springv1alpha1.SpringBootApplication) error {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      metricsServiceName(app),
+			Namespace: app.Namespace,
+		},
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, svc, func() error {
+		labels := serviceLabels(app)
+		labels[METRICS_LABEL] = "true"
+
+		svc.Labels = labels
+		svc.Spec.ClusterIP = corev1.ClusterIPNone
+		svc.Spec.Ports = []corev1.ServicePort{
+			{
+				Name:       METRICS_PORT_NAME,
+				Port:       METRICS_PORT,
+				TargetPort: intstr.FromInt(managementPort(app)),
+			},
+		}
+
+		// Every version is scraped, including canaries and both blue/green colours
+		svc.Spec.Selector = map[string]string{
+			"app": app.Name,
+		}
+
+		return controllerutil.SetControllerReference(app, svc, r.Scheme)
+	})
+
+	return err
+}
+
+// scrapeAnnotations are the prometheus.io annotations put on the pods when metrics are enabled but
+// there's no Prometheus Operator to read a ServiceMonitor
+func (r *SpringBootApplicationReconciler) scrapeAnnotations(app *springv1alpha1.SpringBootApplication) map[string]string {
+	if app.Spec.Metrics == nil || r.serviceMonitorsAvailable() {
+		return nil
+	}
+
+	return map[string]string{
+		"prometheus.io/scrape": "true",
+		"prometheus.io/port":   strconv.Itoa(managementPort(app)),
+		"prometheus.io/path":   metricsPath(app),
+	}
+}
+
+func metricsPath(app *springv1alpha1.SpringBootApplication) string {
+	return actuatorPath(app) + "/prometheus"
+}
@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"context"
+
+	springv1alpha1 "github.com/dante-lor/spring-boot-operator/api/v1alpha1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Creates a PodDisruptionBudget so node drains can't take every replica down at once. A budget
+// would block drains entirely for a single replica, so it is removed when the app can scale down to one
+func (r *SpringBootApplicationReconciler) ensureDisruptionBudget(ctx context.Context, app *springv1alpha1.SpringBootApplication) error {
+	if minReplicas(app) <= 1 {
+		return r.deleteOwned(ctx, app, &policyv1.PodDisruptionBudget{})
+	}
+
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      app.Name,
+			Namespace: app.Namespace,
+		},
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, pdb, func() error {
+		pdb.Labels = app.Labels
+		pdb.Spec = createDisruptionBudgetSpec(app)
+
+		return controllerutil.SetControllerReference(app, pdb, r.Scheme)
+	})
+
+	return err
+}
+
+func createDisruptionBudgetSpec(app *springv1alpha1.SpringBootApplication) policyv1.PodDisruptionBudgetSpec {
+	spec := policyv1.PodDisruptionBudgetSpec{
+		Selector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				"app": app.Name,
+			},
+		},
+		MaxUnavailable: ptr.To(intstr.FromInt(1)),
+	}
+
+	config := app.Spec.Disruption
+
+	if config == nil {
+		return spec
+	}
+
+	// A budget can only have one of the two set
+	if config.MaxUnavailable != nil {
+		spec.MaxUnavailable = config.MaxUnavailable
+	} else if config.MinAvailable != nil {
+		spec.MaxUnavailable = nil
+		spec.MinAvailable = config.MinAvailable
+	}
+
+	return spec
+}
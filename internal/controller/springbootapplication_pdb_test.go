@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"context"
+
+	springv1alpha1 "github.com/dante-lor/spring-boot-operator/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("PDB Controller", func() {
+	const resourceName = "test-pdb"
+	const namespace = "default"
+
+	var (
+		ctx                  context.Context
+		typeNamespacedName   types.NamespacedName
+		controllerReconciler *SpringBootApplicationReconciler
+		app                  *springv1alpha1.SpringBootApplication
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		typeNamespacedName = types.NamespacedName{
+			Name:      resourceName,
+			Namespace: namespace,
+		}
+
+		app = &springv1alpha1.SpringBootApplication{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      resourceName,
+				Namespace: namespace,
+			},
+			Spec: springv1alpha1.SpringBootApplicationSpec{
+				Type:           springv1alpha1.SpringWeb,
+				Image:          "test",
+				ResourcePreset: ptr.To(springv1alpha1.Small),
+				Autoscaler: springv1alpha1.AutoscalingConfig{
+					MinReplicas: 2,
+					MaxReplicas: 5,
+				},
+			},
+		}
+
+		controllerReconciler = &SpringBootApplicationReconciler{
+			Client: k8sClient,
+			Scheme: k8sClient.Scheme(),
+		}
+
+		By("creating the SpringBootApplication resource")
+		Expect(k8sClient.Create(ctx, app)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		By("deleting the SpringBootApplication resource")
+		Expect(k8sClient.Delete(ctx, app)).To(Succeed())
+	})
+
+	It("should create a PDB allowing one pod to be unavailable by default", func() {
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: typeNamespacedName,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		pdb := &policyv1.PodDisruptionBudget{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, pdb)).To(Succeed())
+
+		Expect(*pdb.Spec.MaxUnavailable).To(Equal(intstr.FromInt(1)))
+		Expect(pdb.Spec.MinAvailable).To(BeNil())
+		Expect(pdb.Spec.Selector.MatchLabels).To(HaveKeyWithValue("app", resourceName))
+	})
+
+	It("should use the disruption settings from the spec", func() {
+		app.Spec.Disruption = &springv1alpha1.DisruptionConfig{
+			MinAvailable: ptr.To(intstr.FromString("50%")),
+		}
+		Expect(k8sClient.Update(ctx, app)).To(Succeed())
+
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: typeNamespacedName,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		pdb := &policyv1.PodDisruptionBudget{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, pdb)).To(Succeed())
+
+		Expect(*pdb.Spec.MinAvailable).To(Equal(intstr.FromString("50%")))
+		Expect(pdb.Spec.MaxUnavailable).To(BeNil())
+	})
+
+	It("should remove the PDB when the app can scale down to a single replica", func() {
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: typeNamespacedName,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		// Reconciling updated the status, so fetch the latest version before changing it
+		Expect(k8sClient.Get(ctx, typeNamespacedName, app)).To(Succeed())
+		app.Spec.Autoscaler.MinReplicas = 1
+		Expect(k8sClient.Update(ctx, app)).To(Succeed())
+
+		_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: typeNamespacedName,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		pdb := &policyv1.PodDisruptionBudget{}
+		err = k8sClient.Get(ctx, typeNamespacedName, pdb)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+})
@@ -0,0 +1,122 @@
+package controller
+
+import (
+	springv1alpha1 "github.com/dante-lor/spring-boot-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+// createProbes builds the liveness, readiness and startup probes from the framework's default timings and
+// any overrides in spec.probes
+func createProbes(app *springv1alpha1.SpringBootApplication) (*corev1.Probe, *corev1.Probe, *corev1.Probe, error) {
+	defaults, err := springv1alpha1.DefaultProbes(app.Spec.Type)
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	custom := springv1alpha1.ProbesConfig{}
+
+	if app.Spec.Probes != nil {
+		custom = *app.Spec.Probes
+	}
+
+	expectedStartupSeconds := *defaults.ExpectedStartupSeconds
+
+	if custom.ExpectedStartupSeconds != nil {
+		expectedStartupSeconds = *custom.ExpectedStartupSeconds
+	}
+
+	healthPath := actuatorPath(app) + "/health"
+
+	startup := mergeProbes(custom.Startup, *defaults.Startup)
+
+	// Allow at least twice as long as the application usually takes to start, unless the threshold is set
+	if custom.Startup == nil || custom.Startup.FailureThreshold == nil {
+		budget := 2*expectedStartupSeconds - ptr.Deref(startup.InitialDelaySeconds, 0)
+		threshold := (budget + *startup.PeriodSeconds - 1) / *startup.PeriodSeconds
+
+		startup.FailureThreshold = ptr.To(max(*startup.FailureThreshold, threshold))
+	}
+
+	liveness := createProbe(app, mergeProbes(custom.Liveness, *defaults.Liveness), healthPath+"/liveness")
+	readiness := createProbe(app, mergeProbes(custom.Readiness, *defaults.Readiness), healthPath+"/readiness")
+
+	return liveness, readiness, createProbe(app, startup, healthPath+"/liveness"), nil
+}
+
+func createProbe(app *springv1alpha1.SpringBootApplication, config springv1alpha1.ProbeConfig, defaultPath string) *corev1.Probe {
+	probe := &corev1.Probe{
+		InitialDelaySeconds: ptr.Deref(config.InitialDelaySeconds, 0),
+		PeriodSeconds:       *config.PeriodSeconds,
+		TimeoutSeconds:      *config.TimeoutSeconds,
+		FailureThreshold:    *config.FailureThreshold,
+		SuccessThreshold:    ptr.Deref(config.SuccessThreshold, 1),
+	}
+
+	// Actuator may be served on its own port
+	port := int32(app.Spec.Port)
+
+	if config.Type == "" || config.Type == springv1alpha1.ProbeHTTP {
+		port = int32(managementPort(app))
+	}
+
+	if config.Port != 0 {
+		port = config.Port
+	}
+
+	switch config.Type {
+	case springv1alpha1.ProbeTCP:
+		probe.TCPSocket = &corev1.TCPSocketAction{
+			Port: intstr.FromInt32(port),
+		}
+	case springv1alpha1.ProbeExec:
+		probe.Exec = &corev1.ExecAction{
+			Command: config.Command,
+		}
+	case springv1alpha1.ProbeGRPC:
+		probe.GRPC = &corev1.GRPCAction{
+			Port: port,
+		}
+
+		if config.Service != "" {
+			probe.GRPC.Service = ptr.To(config.Service)
+		}
+	default:
+		path := defaultPath
+
+		if config.Path != "" {
+			path = config.Path
+		}
+
+		probe.HTTPGet = &corev1.HTTPGetAction{
+			Port: intstr.FromInt32(port),
+			Path: path,
+		}
+	}
+
+	return probe
+}
+
+func mergeProbes(custom *springv1alpha1.ProbeConfig, defaults springv1alpha1.ProbeConfig) springv1alpha1.ProbeConfig {
+	if custom == nil {
+		return defaults
+	}
+
+	merged := *custom
+
+	if merged.PeriodSeconds == nil {
+		merged.PeriodSeconds = defaults.PeriodSeconds
+	}
+
+	if merged.TimeoutSeconds == nil {
+		merged.TimeoutSeconds = defaults.TimeoutSeconds
+	}
+
+	if merged.FailureThreshold == nil {
+		merged.FailureThreshold = defaults.FailureThreshold
+	}
+
+	return merged
+}
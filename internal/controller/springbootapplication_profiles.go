@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	springv1alpha1 "github.com/dante-lor/spring-boot-operator/api/v1alpha1"
+	"sigs.k8s.io/yaml"
+)
+
+const APPLICATION_CONFIG_FILE = "application.yaml"
+
+// generateConfigFiles renders the files for the application's ConfigMap: the merged application.yaml
+// and an application-<profile>.yaml for each profile config. Spring loads the profile files on top of
+// application.yaml when the profile is active.
+func generateConfigFiles(spec springv1alpha1.SpringBootApplicationSpec) (map[string]string, error) {
+	config, err := mergeConfig(spec)
+
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string]string{
+		APPLICATION_CONFIG_FILE: config,
+	}
+
+	for profile, raw := range spec.ProfileConfigs {
+		profileConfig := map[string]interface{}{}
+
+		if len(raw.Raw) > 0 {
+			if err := json.Unmarshal(raw.Raw, &profileConfig); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal config for profile %s: %w", profile, err)
+			}
+		}
+
+		yamlBytes, err := yaml.Marshal(profileConfig)
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal config for profile %s to YAML: %w", profile, err)
+		}
+
+		files[profileConfigFile(profile)] = string(yamlBytes)
+	}
+
+	return files, nil
+}
+
+func profileConfigFile(profile string) string {
+	return fmt.Sprintf("application-%s.yaml", profile)
+}
+
+// sortedProfileConfigFiles lists the profile config files in a stable order, so that hashing them
+// doesn't depend on map ordering
+func sortedProfileConfigFiles(files map[string]string) []string {
+	names := []string{}
+
+	for name := range files {
+		if name != APPLICATION_CONFIG_FILE {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	springv1alpha1 "github.com/dante-lor/spring-boot-operator/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	REVISION_ANNOTATION   = "deployment.kubernetes.io/revision"
+	ROLLED_BACK_CONDITION = "RolledBack"
+)
+
+// progressDeadlineExceeded is true once the deployment has given up waiting for the rollout of its current
+// template to progress
+func progressDeadlineExceeded(deploy *appsv1.Deployment) bool {
+	// The condition could be about the template before the last update
+	if deploy.Status.ObservedGeneration < deploy.Generation {
+		return false
+	}
+
+	for _, condition := range deploy.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Reason == "ProgressDeadlineExceeded" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// recordRevision remembers the deployment's revision once it has rolled out, so a later failed rollout
+// can be rolled back to it
+func recordRevision(app *springv1alpha1.SpringBootApplication, deploy *appsv1.Deployment) {
+	revision := deploy.Annotations[REVISION_ANNOTATION]
+
+	if revision == "" || !rolloutComplete(deploy) || progressDeadlineExceeded(deploy) {
+		return
+	}
+
+	app.Status.LastGoodRevision = &springv1alpha1.DeploymentRevision{
+		Revision:     revision,
+		Image:        deploy.Spec.Template.Spec.Containers[0].Image,
+		TemplateHash: deploy.Annotations[TEMPLATE_HASH_ANNOTATION],
+	}
+}
+
+// rollbackTemplate returns the pod template, and its hash, the deployment should run instead of the desired
+// one if a rollout has failed. The deployment is returned to the last good revision, and kept there until
+// the desired template changes. Returns nil and the desired hash if the desired template should be rolled out.
+func (r *SpringBootApplicationReconciler) rollbackTemplate(ctx context.Context, app *springv1alpha1.SpringBootApplication, deploy *appsv1.Deployment, hash string) (*corev1.PodTemplateSpec, string, error) {
+	status := &app.Status
+
+	if status.FailedTemplateHash != "" {
+		if status.FailedTemplateHash == hash {
+			return &deploy.Spec.Template, deploy.Annotations[TEMPLATE_HASH_ANNOTATION], nil
+		}
+
+		// Any change to the spec gets a fresh attempt
+		status.FailedImage = ""
+		status.FailedTemplateHash = ""
+		meta.RemoveStatusCondition(&status.Conditions, ROLLED_BACK_CONDITION)
+	}
+
+	last := status.LastGoodRevision
+
+	if !progressDeadlineExceeded(deploy) || last == nil || deploy.Annotations[REVISION_ANNOTATION] == last.Revision {
+		return nil, hash, nil
+	}
+
+	template, err := r.revisionTemplate(ctx, deploy, last.Revision)
+
+	// Kubernetes only keeps a limited number of old revisions, there's nothing to go back to if it has been removed
+	if err != nil || template == nil {
+		return nil, hash, err
+	}
+
+	status.FailedImage = app.Spec.Image
+	status.FailedTemplateHash = hash
+
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               ROLLED_BACK_CONDITION,
+		Status:             metav1.ConditionTrue,
+		Reason:             "ProgressDeadlineExceeded",
+		Message:            fmt.Sprintf("%s failed to roll out, rolled back to %s until the spec is changed", deploy.Spec.Template.Spec.Containers[0].Image, last.Image),
+		ObservedGeneration: app.Generation,
+	})
+
+	return template, last.TemplateHash, nil
+}
+
+// revisionTemplate finds the pod template of a revision from the deployment's ReplicaSets
+func (r *SpringBootApplicationReconciler) revisionTemplate(ctx context.Context, deploy *appsv1.Deployment, revision string) (*corev1.PodTemplateSpec, error) {
+	replicaSets := &appsv1.ReplicaSetList{}
+	err := r.List(ctx, replicaSets, client.InNamespace(deploy.Namespace), client.MatchingLabels(deploy.Spec.Selector.MatchLabels))
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rs := range replicaSets.Items {
+		if !metav1.IsControlledBy(&rs, deploy) || rs.Annotations[REVISION_ANNOTATION] != revision {
+			continue
+		}
+
+		template := rs.Spec.Template.DeepCopy()
+
+		// Added by the deployment to tell its ReplicaSets apart
+		delete(template.Labels, appsv1.DefaultDeploymentUniqueLabelKey)
+
+		return template, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,58 @@
+package controller
+
+import (
+	springv1alpha1 "github.com/dante-lor/spring-boot-operator/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/utils/ptr"
+)
+
+// createRolloutSettings works out the rolling update settings for the deployment, using the framework's
+// defaults for anything not set in spec.rollout
+func createRolloutSettings(app *springv1alpha1.SpringBootApplication) (springv1alpha1.RolloutConfig, error) {
+	defaults, err := springv1alpha1.DefaultRollout(app.Spec.Type)
+
+	if err != nil {
+		return defaults, err
+	}
+
+	if app.Spec.Rollout == nil {
+		return defaults, nil
+	}
+
+	return mergeRollouts(*app.Spec.Rollout, defaults), nil
+}
+
+func mergeRollouts(custom springv1alpha1.RolloutConfig, defaults springv1alpha1.RolloutConfig) springv1alpha1.RolloutConfig {
+	if custom.MaxSurge == nil {
+		custom.MaxSurge = defaults.MaxSurge
+	}
+
+	if custom.MaxUnavailable == nil {
+		custom.MaxUnavailable = defaults.MaxUnavailable
+	}
+
+	if custom.MinReadySeconds == nil {
+		custom.MinReadySeconds = defaults.MinReadySeconds
+	}
+
+	if custom.ProgressDeadlineSeconds == nil {
+		custom.ProgressDeadlineSeconds = defaults.ProgressDeadlineSeconds
+
+		// Kubernetes requires the deadline to be longer than minReadySeconds
+		if *custom.ProgressDeadlineSeconds <= *custom.MinReadySeconds {
+			custom.ProgressDeadlineSeconds = ptr.To(*custom.MinReadySeconds + *defaults.ProgressDeadlineSeconds)
+		}
+	}
+
+	return custom
+}
+
+func createDeploymentStrategy(rollout springv1alpha1.RolloutConfig) appsv1.DeploymentStrategy {
+	return appsv1.DeploymentStrategy{
+		Type: appsv1.RollingUpdateDeploymentStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateDeployment{
+			MaxSurge:       rollout.MaxSurge,
+			MaxUnavailable: rollout.MaxUnavailable,
+		},
+	}
+}
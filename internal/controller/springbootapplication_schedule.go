@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	springv1alpha1 "github.com/dante-lor/spring-boot-operator/api/v1alpha1"
+	"github.com/robfig/cron/v3"
+)
+
+// evaluateSchedules finds the first scaling schedule whose window is open at the given time, along with the
+// next time any window opens or closes so the application can be reconciled again then
+func evaluateSchedules(schedules []springv1alpha1.ScalingSchedule, now time.Time) (*springv1alpha1.ScalingSchedule, time.Time, error) {
+	var active *springv1alpha1.ScalingSchedule
+	var next time.Time
+
+	for i := range schedules {
+		schedule := &schedules[i]
+
+		location, err := time.LoadLocation(schedule.TimeZone)
+
+		if err != nil {
+			return nil, next, fmt.Errorf("invalid time zone for schedule %s: %w", schedule.Name, err)
+		}
+
+		start, err := cron.ParseStandard(schedule.Start)
+
+		if err != nil {
+			return nil, next, fmt.Errorf("invalid start for schedule %s: %w", schedule.Name, err)
+		}
+
+		end, err := cron.ParseStandard(schedule.End)
+
+		if err != nil {
+			return nil, next, fmt.Errorf("invalid end for schedule %s: %w", schedule.Name, err)
+		}
+
+		nextStart := start.Next(now.In(location))
+		nextEnd := end.Next(now.In(location))
+
+		// If the window closes before it next opens, it must currently be open
+		if active == nil && !nextEnd.IsZero() && (nextStart.IsZero() || nextEnd.Before(nextStart)) {
+			active = schedule
+		}
+
+		for _, boundary := range []time.Time{nextStart, nextEnd} {
+			if !boundary.IsZero() && (next.IsZero() || boundary.Before(next)) {
+				next = boundary
+			}
+		}
+	}
+
+	return active, next, nil
+}
+
+// activeSchedule is the scaling schedule currently in effect, as worked out at the start of the reconcile
+func activeSchedule(app *springv1alpha1.SpringBootApplication) *springv1alpha1.ScalingSchedule {
+	if app.Status.ActiveSchedule == "" {
+		return nil
+	}
+
+	for i := range app.Spec.Autoscaler.Schedules {
+		if app.Spec.Autoscaler.Schedules[i].Name == app.Status.ActiveSchedule {
+			return &app.Spec.Autoscaler.Schedules[i]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	springv1alpha1 "github.com/dante-lor/spring-boot-operator/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	scalingv2 "k8s.io/api/autoscaling/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Scaling schedules", func() {
+	daytime := springv1alpha1.ScalingSchedule{
+		Name:        "daytime",
+		Start:       "0 8 * * 1-5",
+		End:         "0 18 * * 1-5",
+		TimeZone:    "Europe/London",
+		MinReplicas: ptr.To(int32(4)),
+		MaxReplicas: ptr.To(int32(20)),
+	}
+
+	london, _ := time.LoadLocation("Europe/London")
+
+	It("is active inside the window and requeues when it closes", func() {
+		// A Wednesday
+		now := time.Date(2026, time.March, 4, 12, 0, 0, 0, london)
+
+		active, next, err := evaluateSchedules([]springv1alpha1.ScalingSchedule{daytime}, now)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(active).NotTo(BeNil())
+		Expect(active.Name).To(Equal("daytime"))
+		Expect(next).To(BeTemporally("==", time.Date(2026, time.March, 4, 18, 0, 0, 0, london)))
+	})
+
+	It("is inactive outside the window and requeues when it opens", func() {
+		// A Saturday
+		now := time.Date(2026, time.March, 7, 12, 0, 0, 0, london)
+
+		active, next, err := evaluateSchedules([]springv1alpha1.ScalingSchedule{daytime}, now)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(active).To(BeNil())
+		Expect(next).To(BeTemporally("==", time.Date(2026, time.March, 9, 8, 0, 0, 0, london)))
+	})
+
+	It("uses the time zone of the schedule", func() {
+		// 07:30 UTC is 08:30 in London during summer time
+		now := time.Date(2026, time.June, 3, 7, 30, 0, 0, time.UTC)
+
+		active, _, err := evaluateSchedules([]springv1alpha1.ScalingSchedule{daytime}, now)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(active).NotTo(BeNil())
+	})
+
+	Describe("when reconciled", func() {
+		const resourceName = "test-schedule"
+		const namespace = "default"
+
+		var (
+			ctx                  context.Context
+			typeNamespacedName   types.NamespacedName
+			controllerReconciler *SpringBootApplicationReconciler
+			app                  *springv1alpha1.SpringBootApplication
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			typeNamespacedName = types.NamespacedName{
+				Name:      resourceName,
+				Namespace: namespace,
+			}
+
+			app = &springv1alpha1.SpringBootApplication{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: namespace,
+				},
+				Spec: springv1alpha1.SpringBootApplicationSpec{
+					Type:           springv1alpha1.SpringWeb,
+					Image:          "test",
+					ResourcePreset: ptr.To(springv1alpha1.Small),
+					Autoscaler: springv1alpha1.AutoscalingConfig{
+						MinReplicas: 2,
+						MaxReplicas: 5,
+						Schedules: []springv1alpha1.ScalingSchedule{
+							{
+								// Always open, since it closes before it next opens
+								Name:        "always",
+								Start:       "0 0 1 1 *",
+								End:         "* * * * *",
+								MinReplicas: ptr.To(int32(6)),
+								MaxReplicas: ptr.To(int32(12)),
+							},
+						},
+					},
+				},
+			}
+
+			controllerReconciler = &SpringBootApplicationReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			Expect(k8sClient.Create(ctx, app)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(k8sClient.Delete(ctx, app)).To(Succeed())
+		})
+
+		It("uses the replicas of the active schedule and requeues at the next boundary", func() {
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeNumerically("<=", time.Minute))
+
+			hpa := &scalingv2.HorizontalPodAutoscaler{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, hpa)).To(Succeed())
+
+			Expect(*hpa.Spec.MinReplicas).To(Equal(int32(6)))
+			Expect(hpa.Spec.MaxReplicas).To(Equal(int32(12)))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, app)).To(Succeed())
+			Expect(app.Status.ActiveSchedule).To(Equal("always"))
+		})
+	})
+})
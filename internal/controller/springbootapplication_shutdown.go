@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"fmt"
+
+	springv1alpha1 "github.com/dante-lor/spring-boot-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	DEFAULT_SHUTDOWN_TIMEOUT_SECONDS = 30
+	DEFAULT_PRE_STOP_SECONDS         = 5
+
+	// Extra time on top of the shutdown timeout for the JVM to exit before the pod is killed
+	SHUTDOWN_BUFFER_SECONDS = 5
+)
+
+func shutdownTimeoutSeconds(shutdown *springv1alpha1.ShutdownConfig) int32 {
+	if shutdown == nil {
+		return DEFAULT_SHUTDOWN_TIMEOUT_SECONDS
+	}
+
+	return ptr.Deref(shutdown.TimeoutSeconds, DEFAULT_SHUTDOWN_TIMEOUT_SECONDS)
+}
+
+func preStopSeconds(shutdown *springv1alpha1.ShutdownConfig) int32 {
+	if shutdown == nil {
+		return DEFAULT_PRE_STOP_SECONDS
+	}
+
+	return ptr.Deref(shutdown.PreStopSeconds, DEFAULT_PRE_STOP_SECONDS)
+}
+
+// terminationGracePeriodSeconds leaves enough time for the preStop hook and spring's graceful shutdown
+func terminationGracePeriodSeconds(shutdown *springv1alpha1.ShutdownConfig) int64 {
+	return int64(preStopSeconds(shutdown) + shutdownTimeoutSeconds(shutdown) + SHUTDOWN_BUFFER_SECONDS)
+}
+
+// createLifecycle adds a preStop sleep so the pod stops receiving traffic before spring starts shutting down
+func createLifecycle(shutdown *springv1alpha1.ShutdownConfig) *corev1.Lifecycle {
+	seconds := preStopSeconds(shutdown)
+
+	if seconds == 0 {
+		return nil
+	}
+
+	return &corev1.Lifecycle{
+		PreStop: &corev1.LifecycleHandler{
+			// Uses the kubelet's sleep rather than exec, since minimal images may not have a sleep binary
+			Sleep: &corev1.SleepAction{
+				Seconds: int64(seconds),
+			},
+		},
+	}
+}
+
+// mergeShutdownConfig turns on spring's graceful shutdown
+func mergeShutdownConfig(config map[string]interface{}, shutdown *springv1alpha1.ShutdownConfig) {
+	nestedMap(config, "server")["shutdown"] = "graceful"
+	nestedMap(config, "spring", "lifecycle")["timeout-per-shutdown-phase"] = fmt.Sprintf("%ds", shutdownTimeoutSeconds(shutdown))
+}
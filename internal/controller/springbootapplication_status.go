@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	springv1alpha1 "github.com/dante-lor/spring-boot-operator/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	scalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// updateStatus reports the state of the Deployment serving traffic and the HorizontalPodAutoscaler on the application
+func (r *SpringBootApplicationReconciler) updateStatus(ctx context.Context, app *springv1alpha1.SpringBootApplication) error {
+	deploy := &appsv1.Deployment{}
+
+	err := r.Get(ctx, client.ObjectKey{Namespace: app.Namespace, Name: activeDeploymentName(app)}, deploy)
+
+	if client.IgnoreNotFound(err) != nil {
+		return err
+	}
+
+	hpa := &scalingv2.HorizontalPodAutoscaler{}
+	err = r.Get(ctx, client.ObjectKey{Namespace: app.Namespace, Name: hpaName(app)}, hpa)
+
+	if client.IgnoreNotFound(err) != nil {
+		return err
+	}
+
+	if err != nil {
+		hpa = nil
+	}
+
+	app.Status.ServiceURL = serviceURL(app)
+	app.Status.Replicas = deploy.Status.Replicas
+	app.Status.Selector = fmt.Sprintf("app=%s", app.Name)
+	app.Status.ReadyReplicas = deploy.Status.ReadyReplicas
+	app.Status.DesiredReplicas = ptr.Deref(deploy.Spec.Replicas, 0)
+	app.Status.AutoscalerReplicas = 0
+
+	if hpa != nil {
+		app.Status.AutoscalerReplicas = hpa.Status.CurrentReplicas
+
+		// The autoscaler only reports a desired count once it has started scaling
+		if hpa.Status.DesiredReplicas > 0 {
+			app.Status.DesiredReplicas = hpa.Status.DesiredReplicas
+		}
+	}
+
+	if rolloutComplete(deploy) && len(deploy.Spec.Template.Spec.Containers) > 0 {
+		app.Status.Image = deploy.Spec.Template.Spec.Containers[0].Image
+	}
+
+	for _, condition := range computeConditions(deploy, hpa) {
+		condition.ObservedGeneration = app.Generation
+		meta.SetStatusCondition(&app.Status.Conditions, condition)
+	}
+
+	return r.Status().Update(ctx, app)
+}
+
+// serviceURL is the address of the application's service from inside the cluster
+func serviceURL(app *springv1alpha1.SpringBootApplication) string {
+	return fmt.Sprintf("http://%s.%s.svc%s", app.Name, app.Namespace, exposedPath(app.Spec.ContextPath))
+}
+
+// rolloutComplete is true once every replica of the deployment is running the latest pod template
+func rolloutComplete(deploy *appsv1.Deployment) bool {
+	if deploy.Generation == 0 || deploy.Status.ObservedGeneration < deploy.Generation {
+		return false
+	}
+
+	desired := ptr.Deref(deploy.Spec.Replicas, 0)
+
+	return deploy.Status.UpdatedReplicas == desired &&
+		deploy.Status.Replicas == desired &&
+		deploy.Status.AvailableReplicas == desired
+}
+
+// computeConditions works out the Available, Progressing and Degraded conditions from the
+// deployment and (optional) autoscaler
+func computeConditions(deploy *appsv1.Deployment, hpa *scalingv2.HorizontalPodAutoscaler) []metav1.Condition {
+	available := metav1.Condition{
+		Type:    "Available",
+		Status:  metav1.ConditionFalse,
+		Reason:  "NoReplicasAvailable",
+		Message: "No replicas are ready to serve traffic",
+	}
+
+	if deploy.Status.AvailableReplicas > 0 {
+		available.Status = metav1.ConditionTrue
+		available.Reason = "ReplicasAvailable"
+		available.Message = fmt.Sprintf("%d of %d replicas are available", deploy.Status.AvailableReplicas, deploy.Status.Replicas)
+	}
+
+	progressing := metav1.Condition{
+		Type:    "Progressing",
+		Status:  metav1.ConditionFalse,
+		Reason:  "RolloutComplete",
+		Message: "All replicas are running the latest version",
+	}
+
+	if !rolloutComplete(deploy) {
+		progressing.Status = metav1.ConditionTrue
+		progressing.Reason = "RollingOut"
+		progressing.Message = fmt.Sprintf("%d of %d replicas have been updated", deploy.Status.UpdatedReplicas, ptr.Deref(deploy.Spec.Replicas, 0))
+	}
+
+	degraded := metav1.Condition{
+		Type:    "Degraded",
+		Status:  metav1.ConditionFalse,
+		Reason:  "Healthy",
+		Message: "The application is healthy",
+	}
+
+	for _, condition := range deploy.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Reason == "ProgressDeadlineExceeded" {
+			progressing.Status = metav1.ConditionFalse
+			progressing.Reason = condition.Reason
+			progressing.Message = condition.Message
+
+			degraded.Status = metav1.ConditionTrue
+			degraded.Reason = condition.Reason
+			degraded.Message = condition.Message
+		}
+
+		if condition.Type == appsv1.DeploymentReplicaFailure && condition.Status == corev1.ConditionTrue {
+			degraded.Status = metav1.ConditionTrue
+			degraded.Reason = "ReplicaFailure"
+			degraded.Message = condition.Message
+		}
+	}
+
+	if hpa != nil && degraded.Status == metav1.ConditionFalse {
+		for _, condition := range hpa.Status.Conditions {
+			// The autoscaler disables itself while KEDA has scaled an idle app to zero, which is expected
+			if condition.Type == scalingv2.ScalingActive && condition.Status == corev1.ConditionFalse && condition.Reason != "ScalingDisabled" {
+				degraded.Status = metav1.ConditionTrue
+				degraded.Reason = "AutoscalingInactive"
+				degraded.Message = condition.Message
+			}
+		}
+	}
+
+	return []metav1.Condition{available, progressing, degraded}
+}
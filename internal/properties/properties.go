@@ -0,0 +1,273 @@
+/*
+Copyright 2026 Daniel Taylor.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package properties parses Java .properties files into the nested structure of the equivalent yaml, and
+// looks properties up the way Spring's relaxed binding does
+package properties
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Caps list indexes, so a typo can't allocate a huge list
+const maxListIndex = 1000
+
+type segment struct {
+	name    string
+	index   int
+	isIndex bool
+}
+
+// Parse reads properties into nested maps, splitting keys on dots. Indexed keys such as a.b[0].c become
+// lists, and bracketed keys such as logging.level[com.example] keep their dots. Values are strings, as
+// they are in the properties file.
+func Parse(input string) (map[string]interface{}, error) {
+	var root interface{} = map[string]interface{}{}
+
+	for _, line := range logicalLines(input) {
+		key, value := splitLine(line)
+
+		path, err := parseKey(key)
+
+		if err != nil {
+			return nil, err
+		}
+
+		root, err = set(root, path, value, key)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return root.(map[string]interface{}), nil
+}
+
+// logicalLines joins continued lines (ending in an odd number of backslashes) and drops blank lines
+// and comments
+func logicalLines(input string) []string {
+	lines := []string{}
+	current := ""
+	continuing := false
+
+	for _, line := range strings.Split(strings.ReplaceAll(input, "\r\n", "\n"), "\n") {
+		line = strings.TrimLeft(line, " \t\f")
+
+		if !continuing && (line == "" || line[0] == '#' || line[0] == '!') {
+			continue
+		}
+
+		trailing := len(line) - len(strings.TrimRight(line, "\\"))
+
+		if trailing%2 == 1 {
+			current += line[:len(line)-1]
+			continuing = true
+			continue
+		}
+
+		lines = append(lines, current+line)
+		current = ""
+		continuing = false
+	}
+
+	if continuing {
+		lines = append(lines, current)
+	}
+
+	return lines
+}
+
+// splitLine splits the line on the first unescaped =, : or whitespace
+func splitLine(line string) (string, string) {
+	end := len(line)
+
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+
+		if c == '\\' {
+			i++
+			continue
+		}
+
+		if c == '=' || c == ':' || c == ' ' || c == '\t' || c == '\f' {
+			end = i
+			break
+		}
+	}
+
+	value := strings.TrimLeft(line[end:], " \t\f")
+
+	if value != "" && (value[0] == '=' || value[0] == ':') {
+		value = strings.TrimLeft(value[1:], " \t\f")
+	}
+
+	return unescape(line[:end]), unescape(value)
+}
+
+func unescape(s string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		i++
+
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if i+4 < len(s) {
+				if code, err := strconv.ParseUint(s[i+1:i+5], 16, 32); err == nil {
+					b.WriteRune(rune(code))
+					i += 4
+					continue
+				}
+			}
+
+			b.WriteByte('u')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+
+	return b.String()
+}
+
+// parseKey splits a key such as a.b[0].c into its names and list indexes
+func parseKey(key string) ([]segment, error) {
+	segments := []segment{}
+	var name strings.Builder
+
+	flush := func() {
+		if name.Len() > 0 {
+			segments = append(segments, segment{name: name.String()})
+			name.Reset()
+		}
+	}
+
+	for i := 0; i < len(key); i++ {
+		switch key[i] {
+		case '.':
+			if name.Len() == 0 && (i == 0 || key[i-1] != ']') {
+				return nil, fmt.Errorf("%q has an empty name", key)
+			}
+
+			flush()
+		case '[':
+			flush()
+
+			end := strings.IndexByte(key[i:], ']')
+
+			if end <= 1 {
+				return nil, fmt.Errorf("%q has an unclosed or empty [", key)
+			}
+
+			inner := key[i+1 : i+end]
+
+			if index, err := strconv.Atoi(inner); err == nil {
+				if index < 0 || index > maxListIndex {
+					return nil, fmt.Errorf("%q has an index out of range", key)
+				}
+
+				segments = append(segments, segment{index: index, isIndex: true})
+			} else {
+				segments = append(segments, segment{name: inner})
+			}
+
+			i += end
+		default:
+			name.WriteByte(key[i])
+		}
+	}
+
+	if strings.HasSuffix(key, ".") {
+		return nil, fmt.Errorf("%q has an empty name", key)
+	}
+
+	flush()
+
+	if len(segments) == 0 || segments[0].isIndex {
+		return nil, fmt.Errorf("%q must start with a name", key)
+	}
+
+	return segments, nil
+}
+
+// set puts the value at the path in the node, returning the updated node. Later values replace earlier
+// ones, like they do in a properties file
+func set(node interface{}, path []segment, value string, key string) (interface{}, error) {
+	if len(path) == 0 {
+		if _, ok := node.(string); node != nil && !ok {
+			return nil, fmt.Errorf("%q conflicts with properties nested under it", key)
+		}
+
+		return value, nil
+	}
+
+	conflict := fmt.Errorf("%q conflicts with another property", key)
+	current := path[0]
+
+	if current.isIndex {
+		list, ok := node.([]interface{})
+
+		if node != nil && !ok {
+			return nil, conflict
+		}
+
+		for len(list) <= current.index {
+			list = append(list, nil)
+		}
+
+		child, err := set(list[current.index], path[1:], value, key)
+
+		if err != nil {
+			return nil, err
+		}
+
+		list[current.index] = child
+
+		return list, nil
+	}
+
+	object, ok := node.(map[string]interface{})
+
+	if node == nil {
+		object = map[string]interface{}{}
+	} else if !ok {
+		return nil, conflict
+	}
+
+	child, err := set(object[current.name], path[1:], value, key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	object[current.name] = child
+
+	return object, nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2026 Daniel Taylor.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package properties
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Parse", func() {
+	It("nests dotted keys", func() {
+		config, err := Parse("server.port=8080\nspring.application.name: orders\nlogging.level.root warn\n")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(config).To(Equal(map[string]interface{}{
+			"server": map[string]interface{}{
+				"port": "8080",
+			},
+			"spring": map[string]interface{}{
+				"application": map[string]interface{}{
+					"name": "orders",
+				},
+			},
+			"logging": map[string]interface{}{
+				"level": map[string]interface{}{
+					"root": "warn",
+				},
+			},
+		}))
+	})
+
+	It("turns indexed keys into lists", func() {
+		config, err := Parse("app.servers[1].host=b\napp.servers[0].host=a\napp.tags[0]=x\n")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(config).To(Equal(map[string]interface{}{
+			"app": map[string]interface{}{
+				"servers": []interface{}{
+					map[string]interface{}{"host": "a"},
+					map[string]interface{}{"host": "b"},
+				},
+				"tags": []interface{}{"x"},
+			},
+		}))
+	})
+
+	It("keeps the dots in bracketed keys", func() {
+		config, err := Parse("logging.level[com.example.orders]=debug")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(config).To(Equal(map[string]interface{}{
+			"logging": map[string]interface{}{
+				"level": map[string]interface{}{
+					"com.example.orders": "debug",
+				},
+			},
+		}))
+	})
+
+	It("skips comments and joins continued lines", func() {
+		config, err := Parse("# comment\n! also a comment\n\napp.greeting=hello \\\n    world\napp.path=C:\\\\temp\napp.key\\=name=\\u00e9\n")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(config).To(Equal(map[string]interface{}{
+			"app": map[string]interface{}{
+				"greeting": "hello world",
+				"path":     `C:\temp`,
+				"key=name": "é",
+			},
+		}))
+	})
+
+	It("uses the last value of repeated keys", func() {
+		config, err := Parse("server.port=8080\nserver.port=9090")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(config["server"]).To(HaveKeyWithValue("port", "9090"))
+	})
+
+	It("rejects keys which can't be represented as yaml", func() {
+		_, err := Parse("app=1\napp.name=orders")
+		Expect(err).To(HaveOccurred())
+
+		_, err = Parse("app.name=orders\napp=1")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("rejects malformed keys", func() {
+		for _, key := range []string{"app..name", ".app", "app.", "[0]", "app[", "app[]", "app[5000]"} {
+			_, err := Parse(key + "=value")
+			Expect(err).To(HaveOccurred(), key)
+		}
+	})
+})
@@ -0,0 +1,76 @@
+/*
+Copyright 2026 Daniel Taylor.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package properties
+
+import "strings"
+
+// Lookup finds a nested property, matching keys the way Spring's relaxed binding does (so contextPath
+// and context_path both match context-path), preferring an exact match
+func Lookup(config map[string]interface{}, keys ...string) (interface{}, bool) {
+	var current interface{} = config
+
+	for _, key := range keys {
+		object, ok := current.(map[string]interface{})
+
+		if !ok {
+			return nil, false
+		}
+
+		value, found := object[key]
+
+		for existing, existingValue := range object {
+			if !found && uniformKey(existing) == uniformKey(key) {
+				value = existingValue
+				found = true
+			}
+		}
+
+		if !found {
+			return nil, false
+		}
+
+		current = value
+	}
+
+	return current, true
+}
+
+// Take removes every key Spring's relaxed binding treats as the same as the given one (for example
+// contextPath and context_path for context-path) and returns its value, preferring an exact match
+func Take(config map[string]interface{}, key string) interface{} {
+	value, found := config[key]
+
+	for existing, existingValue := range config {
+		if uniformKey(existing) != uniformKey(key) {
+			continue
+		}
+
+		if !found {
+			value = existingValue
+			found = true
+		}
+
+		delete(config, existing)
+	}
+
+	return value
+}
+
+// uniformKey drops the separators and case relaxed binding ignores
+func uniformKey(key string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", "_", "").Replace(key))
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2026 Daniel Taylor.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package properties
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Relaxed binding", func() {
+	config := func() map[string]interface{} {
+		return map[string]interface{}{
+			"server": map[string]interface{}{
+				"servlet": map[string]interface{}{
+					"contextPath":  "/camel",
+					"context_path": "/snake",
+				},
+			},
+			"Management": map[string]interface{}{
+				"server": map[string]interface{}{
+					"port": 9090,
+				},
+			},
+		}
+	}
+
+	It("looks up properties whatever their spelling", func() {
+		value, ok := Lookup(config(), "management", "server", "port")
+		Expect(ok).To(BeTrue())
+		Expect(value).To(Equal(9090))
+
+		value, ok = Lookup(config(), "server", "servlet", "context-path")
+		Expect(ok).To(BeTrue())
+		Expect(value).To(BeElementOf("/camel", "/snake"))
+	})
+
+	It("prefers an exact match when looking up", func() {
+		value, ok := Lookup(config(), "server", "servlet", "contextPath")
+		Expect(ok).To(BeTrue())
+		Expect(value).To(Equal("/camel"))
+	})
+
+	It("doesn't find missing properties", func() {
+		_, ok := Lookup(config(), "server", "port")
+		Expect(ok).To(BeFalse())
+
+		_, ok = Lookup(config(), "server", "servlet", "context-path", "nested")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("takes every spelling of a property", func() {
+		servlet := config()["server"].(map[string]interface{})["servlet"].(map[string]interface{})
+
+		Expect(Take(servlet, "context_path")).To(Equal("/snake"))
+		Expect(servlet).To(BeEmpty())
+
+		Expect(Take(servlet, "context-path")).To(BeNil())
+	})
+})
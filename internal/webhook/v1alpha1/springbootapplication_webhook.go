@@ -18,15 +18,28 @@ package v1alpha1
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
 
+	"github.com/robfig/cron/v3"
+	scalingv2 "k8s.io/api/autoscaling/v2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	springv1alpha1 "github.com/dante-lor/spring-boot-operator/api/v1alpha1"
+	"github.com/dante-lor/spring-boot-operator/internal/properties"
 )
 
 // log is for logging in this package.
@@ -36,6 +49,7 @@ var springbootapplicationlog = logf.Log.WithName("springbootapplication-resource
 func SetupSpringBootApplicationWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).For(&springv1alpha1.SpringBootApplication{}).
 		WithDefaulter(&SpringBootApplicationResourceDefaulter{}).
+		WithValidator(&SpringBootApplicationCustomValidator{}).
 		Complete()
 }
 
@@ -65,3 +79,663 @@ func (d *SpringBootApplicationResourceDefaulter) Default(_ context.Context, obj
 
 	return nil
 }
+
+// +kubebuilder:webhook:path=/validate-spring-dante-lor-github-io-v1alpha1-springbootapplication,mutating=false,failurePolicy=fail,sideEffects=None,groups=spring.dante-lor.github.io,resources=springbootapplications,verbs=create;update,versions=v1alpha1,name=vspringbootapplication-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// SpringBootApplicationCustomValidator struct is responsible for validating the SpringBootApplication resource
+// when it is created or updated, so that bad specs are rejected instead of failing during reconciliation.
+type SpringBootApplicationCustomValidator struct {
+}
+
+var _ webhook.CustomValidator = &SpringBootApplicationCustomValidator{}
+
+// Environment variables set by the operator which users aren't allowed to override
+var reservedEnvVars = []string{"SPRING_CONFIG_ADDITIONAL_LOCATION", "JAVA_TOOL_OPTIONS"}
+
+// Paths the operator mounts volumes at, which user volumes can't be mounted over or inside of
+var reservedMountPaths = []string{"/config", "/config-secrets", "/config-from", "/tmp"}
+
+var contextPathPattern = regexp.MustCompile(`^/([A-Za-z0-9._~%!$&'()*+,;=:@-]+/?)*$`)
+
+// Matches the pattern on spec.profiles
+var profilePattern = regexp.MustCompile(`^[a-zA-Z0-9][-a-zA-Z0-9_.]*$`)
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type SpringBootApplication.
+func (v *SpringBootApplicationCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	springbootapplication, ok := obj.(*springv1alpha1.SpringBootApplication)
+
+	if !ok {
+		return nil, fmt.Errorf("expected a SpringBootApplication object but got %T", obj)
+	}
+	springbootapplicationlog.Info("Validation for SpringBootApplication upon creation", "name", springbootapplication.GetName())
+
+	return nil, validateSpringBootApplication(springbootapplication)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type SpringBootApplication.
+func (v *SpringBootApplicationCustomValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	springbootapplication, ok := newObj.(*springv1alpha1.SpringBootApplication)
+
+	if !ok {
+		return nil, fmt.Errorf("expected a SpringBootApplication object for the newObj but got %T", newObj)
+	}
+	springbootapplicationlog.Info("Validation for SpringBootApplication upon update", "name", springbootapplication.GetName())
+
+	return nil, validateSpringBootApplication(springbootapplication)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type SpringBootApplication.
+func (v *SpringBootApplicationCustomValidator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateSpringBootApplication(app *springv1alpha1.SpringBootApplication) error {
+	specPath := field.NewPath("spec")
+
+	var allErrs field.ErrorList
+
+	allErrs = append(allErrs, validateResources(app.Spec.Resources, specPath.Child("resources"))...)
+	allErrs = append(allErrs, validateJVM(app.Spec, specPath.Child("jvm"))...)
+	allErrs = append(allErrs, validateAutoscaler(app.Spec.Autoscaler, specPath.Child("autoscaler"))...)
+	allErrs = append(allErrs, validateConfig(app.Spec, specPath.Child("config"))...)
+	allErrs = append(allErrs, validateProfiles(app.Spec, specPath.Child("profileConfigs"))...)
+	allErrs = append(allErrs, validateSecrets(app.Spec.Secrets, specPath.Child("secrets"))...)
+	allErrs = append(allErrs, validateConfigFrom(app.Spec.ConfigFrom, specPath.Child("configFrom"))...)
+	allErrs = append(allErrs, validateContextPath(app.Spec.ContextPath, specPath.Child("contextPath"))...)
+	allErrs = append(allErrs, validateManagement(app.Spec, specPath.Child("management"))...)
+	allErrs = append(allErrs, validateDisruption(app.Spec.Disruption, specPath.Child("disruption"))...)
+	allErrs = append(allErrs, validateExpose(app.Spec.Expose, specPath.Child("expose"))...)
+	allErrs = append(allErrs, validateEnv(app.Spec, specPath.Child("env"))...)
+	allErrs = append(allErrs, validateVolumes(app.Spec.Volumes, specPath.Child("volumes"))...)
+	allErrs = append(allErrs, validateRollout(app.Spec, specPath.Child("rollout"))...)
+	allErrs = append(allErrs, validateProbes(app.Spec, specPath.Child("probes"))...)
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(springv1alpha1.GroupVersion.WithKind("SpringBootApplication").GroupKind(), app.Name, allErrs)
+}
+
+func validateResources(resources *springv1alpha1.ResourceDefinition, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if resources == nil {
+		return allErrs
+	}
+
+	if _, err := resource.ParseQuantity(resources.CPU); err != nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("cpu"), resources.CPU, err.Error()))
+	}
+
+	if _, err := resource.ParseQuantity(resources.Memory); err != nil {
+		allErrs = append(allErrs, field.Invalid(path.Child("memory"), resources.Memory, err.Error()))
+	}
+
+	return allErrs
+}
+
+func validateJVM(spec springv1alpha1.SpringBootApplicationSpec, fieldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	jvm := spec.JVM
+
+	if jvm == nil {
+		return allErrs
+	}
+
+	if spec.Type == springv1alpha1.SpringNative {
+		allErrs = append(allErrs, field.Forbidden(fieldPath, "native images don't run on the JVM"))
+	}
+
+	if jvm.HeapDumpPath != "" && !writablePath(spec, jvm.HeapDumpPath) {
+		allErrs = append(allErrs, field.Invalid(fieldPath.Child("heapDumpPath"), jvm.HeapDumpPath, "must be under /tmp or a writable volume, the root filesystem is read only"))
+	}
+
+	for i, flag := range jvm.ExtraFlags {
+		if !strings.HasPrefix(flag, "-") {
+			allErrs = append(allErrs, field.Invalid(fieldPath.Child("extraFlags").Index(i), flag, "must start with -"))
+		}
+	}
+
+	return allErrs
+}
+
+// writablePath checks the path is on /tmp or a volume the application can write to
+func writablePath(spec springv1alpha1.SpringBootApplicationSpec, filePath string) bool {
+	filePath = path.Clean(filePath)
+
+	under := func(dir string) bool {
+		return filePath == dir || strings.HasPrefix(filePath, dir+"/")
+	}
+
+	if under("/tmp") {
+		return true
+	}
+
+	for _, volume := range spec.Volumes {
+		// ConfigMaps and secrets are always mounted read only
+		writable := !volume.ReadOnly && (volume.EmptyDir != nil || volume.PersistentVolumeClaim != nil)
+
+		if writable && under(volume.MountPath) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func validateAutoscaler(autoscaler springv1alpha1.AutoscalingConfig, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if autoscaler.MinReplicas < 1 {
+		allErrs = append(allErrs, field.Invalid(path.Child("minReplicas"), autoscaler.MinReplicas, "must be at least 1"))
+	}
+
+	if autoscaler.MinReplicas > autoscaler.MaxReplicas {
+		allErrs = append(allErrs, field.Invalid(path.Child("minReplicas"), autoscaler.MinReplicas, "must not be greater than maxReplicas"))
+	}
+
+	cpu := autoscaler.TargetUtilization.CpuPercentage
+
+	if cpu != nil && (*cpu < 1 || *cpu > 100) {
+		allErrs = append(allErrs, field.Invalid(path.Child("utilizationTarget", "cpuPercentage"), *cpu, "must be between 1 and 100"))
+	}
+
+	memory := autoscaler.TargetUtilization.MemoryPercentage
+
+	if memory != nil && (*memory < 1 || *memory > 100) {
+		allErrs = append(allErrs, field.Invalid(path.Child("utilizationTarget", "memoryPercentage"), *memory, "must be between 1 and 100"))
+	}
+
+	if autoscaler.Engine == springv1alpha1.EngineKeda && autoscaler.Keda == nil {
+		allErrs = append(allErrs, field.Required(path.Child("keda"), "must be set when using the keda engine"))
+	}
+
+	if autoscaler.Engine != springv1alpha1.EngineKeda && autoscaler.Keda != nil {
+		allErrs = append(allErrs, field.Forbidden(path.Child("keda"), "can only be used with the keda engine"))
+	}
+
+	allErrs = append(allErrs, validateSchedules(autoscaler.Schedules, path.Child("schedules"))...)
+
+	for i, metric := range autoscaler.Metrics {
+		switch metric.Type {
+		case scalingv2.PodsMetricSourceType, scalingv2.ObjectMetricSourceType, scalingv2.ExternalMetricSourceType:
+		default:
+			allErrs = append(allErrs, field.NotSupported(path.Child("metrics").Index(i).Child("type"), metric.Type, []string{
+				string(scalingv2.PodsMetricSourceType),
+				string(scalingv2.ObjectMetricSourceType),
+				string(scalingv2.ExternalMetricSourceType),
+			}))
+		}
+	}
+
+	return allErrs
+}
+
+func validateSchedules(schedules []springv1alpha1.ScalingSchedule, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	names := map[string]bool{}
+
+	for i, schedule := range schedules {
+		schedulePath := path.Index(i)
+
+		if names[schedule.Name] {
+			allErrs = append(allErrs, field.Duplicate(schedulePath.Child("name"), schedule.Name))
+		}
+
+		names[schedule.Name] = true
+
+		if _, err := cron.ParseStandard(schedule.Start); err != nil {
+			allErrs = append(allErrs, field.Invalid(schedulePath.Child("start"), schedule.Start, err.Error()))
+		}
+
+		if _, err := cron.ParseStandard(schedule.End); err != nil {
+			allErrs = append(allErrs, field.Invalid(schedulePath.Child("end"), schedule.End, err.Error()))
+		}
+
+		if _, err := time.LoadLocation(schedule.TimeZone); err != nil {
+			allErrs = append(allErrs, field.Invalid(schedulePath.Child("timeZone"), schedule.TimeZone, err.Error()))
+		}
+
+		if schedule.MinReplicas != nil && schedule.MaxReplicas != nil && *schedule.MinReplicas > *schedule.MaxReplicas {
+			allErrs = append(allErrs, field.Invalid(schedulePath.Child("minReplicas"), *schedule.MinReplicas, "must not be greater than maxReplicas"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateConfig(spec springv1alpha1.SpringBootApplicationSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if spec.Config != nil {
+		allErrs = append(allErrs, validateConfigDocument(*spec.Config, spec, path)...)
+	}
+
+	if spec.ConfigProperties == "" {
+		return allErrs
+	}
+
+	propertiesPath := path.Root().Child("configProperties")
+
+	if spec.Config != nil && len(spec.Config.Raw) > 0 {
+		allErrs = append(allErrs, field.Forbidden(propertiesPath, "can't be set together with config"))
+	}
+
+	config, err := properties.Parse(spec.ConfigProperties)
+
+	if err != nil {
+		return append(allErrs, field.Invalid(propertiesPath, spec.ConfigProperties, err.Error()))
+	}
+
+	return append(allErrs, validateManagedProperties(config, spec, propertiesPath)...)
+}
+
+func validateProfiles(spec springv1alpha1.SpringBootApplicationSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for profile, config := range spec.ProfileConfigs {
+		profilePath := path.Key(profile)
+
+		// The profile name ends up in the ConfigMap key
+		if !profilePattern.MatchString(profile) {
+			allErrs = append(allErrs, field.Invalid(profilePath, profile, "must be a valid profile name"))
+		}
+
+		allErrs = append(allErrs, validateConfigDocument(config, spec, profilePath)...)
+	}
+
+	return allErrs
+}
+
+// validateSecrets checks each secret is only listed once, as they are mounted by name
+func validateSecrets(secrets []springv1alpha1.SecretConfigSource, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	names := map[string]bool{}
+
+	for i, secret := range secrets {
+		if names[secret.Name] {
+			allErrs = append(allErrs, field.Duplicate(path.Index(i).Child("name"), secret.Name))
+		}
+
+		names[secret.Name] = true
+	}
+
+	return allErrs
+}
+
+func validateConfigFrom(sources []springv1alpha1.ConfigFromSource, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, source := range sources {
+		if (source.ConfigMap == "") == (source.Secret == "") {
+			allErrs = append(allErrs, field.Invalid(path.Index(i), source, "exactly one of configMap or secret must be set"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateConfigDocument checks the config is an object which doesn't override the operator managed properties
+func validateConfigDocument(raw runtime.RawExtension, spec springv1alpha1.SpringBootApplicationSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if len(raw.Raw) == 0 {
+		return allErrs
+	}
+
+	config := map[string]interface{}{}
+
+	if err := json.Unmarshal(raw.Raw, &config); err != nil {
+		return append(allErrs, field.Invalid(path, string(raw.Raw), "must be an object"))
+	}
+
+	return validateManagedProperties(config, spec, path)
+}
+
+// validateManagedProperties checks the config doesn't change the port or paths the service and probes
+// are built from. The operator overrides the port and context path anyway, but the probes would fail
+// if actuator was moved.
+func validateManagedProperties(config map[string]interface{}, spec springv1alpha1.SpringBootApplicationSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if port, ok := properties.Lookup(config, "server", "port"); ok && fmt.Sprint(port) != fmt.Sprint(spec.Port) {
+		allErrs = append(allErrs, field.Invalid(path.Child("server", "port"), port, "conflicts with spec.port, set the port there instead"))
+	}
+
+	// Servlet apps serve from server.servlet.context-path and webflux apps from spring.webflux.base-path
+	contextPathKeys := [][]string{}
+
+	if spec.Type != springv1alpha1.SpringWebflux {
+		contextPathKeys = append(contextPathKeys, []string{"server", "servlet", "context-path"})
+	}
+
+	if spec.Type == springv1alpha1.SpringWebflux || spec.Type == springv1alpha1.SpringNative {
+		contextPathKeys = append(contextPathKeys, []string{"spring", "webflux", "base-path"})
+	}
+
+	for _, keys := range contextPathKeys {
+		if contextPath, ok := properties.Lookup(config, keys...); ok && cleanPath(contextPath) != cleanPath(spec.ContextPath) {
+			allErrs = append(allErrs, field.Invalid(path.Child(keys[0], keys[1:]...), contextPath, "conflicts with spec.contextPath, set the context path there instead"))
+		}
+	}
+
+	basePath := "/actuator"
+	managementPort := spec.Port
+
+	if spec.Management != nil {
+		if spec.Management.BasePath != "" {
+			basePath = spec.Management.BasePath
+		}
+
+		if spec.Management.Port != 0 {
+			managementPort = spec.Management.Port
+		}
+	}
+
+	if configBasePath, ok := properties.Lookup(config, "management", "endpoints", "web", "base-path"); ok && cleanPath(configBasePath) != cleanPath(basePath) {
+		allErrs = append(allErrs, field.Invalid(path.Child("management", "endpoints", "web", "base-path"), configBasePath, "conflicts with the actuator path used by the probes, set it with spec.management.basePath instead"))
+	}
+
+	if port, ok := properties.Lookup(config, "management", "server", "port"); ok && fmt.Sprint(port) != fmt.Sprint(managementPort) {
+		allErrs = append(allErrs, field.Invalid(path.Child("management", "server", "port"), port, "conflicts with the port used by the probes, set it with spec.management.port instead"))
+	}
+
+	return allErrs
+}
+
+// cleanPath makes paths with and without trailing slashes comparable
+func cleanPath(value interface{}) string {
+	return "/" + strings.Trim(fmt.Sprint(value), "/")
+}
+
+func validateContextPath(contextPath string, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if !contextPathPattern.MatchString(contextPath) {
+		allErrs = append(allErrs, field.Invalid(path, contextPath, "must start with / and only contain valid URL path segments"))
+	}
+
+	return allErrs
+}
+
+func validateManagement(spec springv1alpha1.SpringBootApplicationSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if spec.Management == nil {
+		return allErrs
+	}
+
+	if spec.Management.Port == spec.Port {
+		allErrs = append(allErrs, field.Invalid(path.Child("port"), spec.Management.Port, "must differ from spec.port, leave it unset to serve actuator on the application port"))
+	}
+
+	return allErrs
+}
+
+func validateDisruption(disruption *springv1alpha1.DisruptionConfig, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if disruption == nil {
+		return allErrs
+	}
+
+	if disruption.MaxUnavailable != nil && disruption.MinAvailable != nil {
+		allErrs = append(allErrs, field.Forbidden(path.Child("minAvailable"), "can't be set together with maxUnavailable"))
+	}
+
+	return allErrs
+}
+
+func validateExpose(expose *springv1alpha1.ExposeConfig, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if expose == nil {
+		return allErrs
+	}
+
+	if expose.Mode == springv1alpha1.ExposeGateway && expose.ParentRef == nil {
+		allErrs = append(allErrs, field.Required(path.Child("parentRef"), "must be set when exposing through a gateway"))
+	}
+
+	if expose.Mode != springv1alpha1.ExposeGateway && expose.ParentRef != nil {
+		allErrs = append(allErrs, field.Forbidden(path.Child("parentRef"), "can only be used when exposing through a gateway"))
+	}
+
+	return allErrs
+}
+
+func validateEnv(spec springv1alpha1.SpringBootApplicationSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, env := range spec.Env {
+		for _, reserved := range reservedEnvVars {
+			if env.Name == reserved {
+				allErrs = append(allErrs, field.Forbidden(path.Index(i).Child("name"), fmt.Sprintf("%s is managed by the operator", env.Name)))
+			}
+		}
+
+		if env.Name == "SPRING_PROFILES_ACTIVE" && len(spec.Profiles) > 0 {
+			allErrs = append(allErrs, field.Forbidden(path.Index(i).Child("name"), "conflicts with spec.profiles"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateVolumes(volumes []springv1alpha1.VolumeConfig, fieldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	names := map[string]bool{}
+	mountPaths := map[string]bool{}
+
+	for i, volume := range volumes {
+		volumePath := fieldPath.Index(i)
+
+		if volume.Name == "config" || volume.Name == "tmp" || strings.HasPrefix(volume.Name, "secret-") || strings.HasPrefix(volume.Name, "config-from-") {
+			allErrs = append(allErrs, field.Forbidden(volumePath.Child("name"), fmt.Sprintf("%s is used by the operator", volume.Name)))
+		} else if names[volume.Name] {
+			allErrs = append(allErrs, field.Duplicate(volumePath.Child("name"), volume.Name))
+		}
+
+		names[volume.Name] = true
+
+		sources := 0
+
+		for _, set := range []bool{volume.EmptyDir != nil, volume.PersistentVolumeClaim != nil, volume.ConfigMap != nil, volume.Secret != nil} {
+			if set {
+				sources++
+			}
+		}
+
+		if sources != 1 {
+			allErrs = append(allErrs, field.Invalid(volumePath, volume.Name, "exactly one of emptyDir, persistentVolumeClaim, configMap or secret must be set"))
+		}
+
+		mountPath := volume.MountPath
+
+		if !path.IsAbs(mountPath) || path.Clean(mountPath) != mountPath || mountPath == "/" {
+			allErrs = append(allErrs, field.Invalid(volumePath.Child("mountPath"), mountPath, "must be a clean absolute path other than /"))
+			continue
+		}
+
+		for _, reserved := range reservedMountPaths {
+			if mountPath == reserved || strings.HasPrefix(mountPath, reserved+"/") || strings.HasPrefix(reserved, mountPath+"/") {
+				allErrs = append(allErrs, field.Forbidden(volumePath.Child("mountPath"), fmt.Sprintf("overlaps with %s which is mounted by the operator", reserved)))
+			}
+		}
+
+		if mountPaths[mountPath] {
+			allErrs = append(allErrs, field.Duplicate(volumePath.Child("mountPath"), mountPath))
+		}
+
+		mountPaths[mountPath] = true
+	}
+
+	return allErrs
+}
+
+func validateRollout(spec springv1alpha1.SpringBootApplicationSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	rollout := spec.Rollout
+
+	if rollout == nil {
+		return allErrs
+	}
+
+	if rollout.BlueGreen != nil && rollout.Strategy != springv1alpha1.BlueGreen {
+		allErrs = append(allErrs, field.Forbidden(path.Child("blueGreen"), "can only be used with the blueGreen strategy"))
+	}
+
+	allErrs = append(allErrs, validateRollingUpdate(spec, path)...)
+
+	if rollout.Canary == nil {
+		return allErrs
+	}
+
+	canaryPath := path.Child("canary")
+
+	if rollout.Strategy != springv1alpha1.Canary {
+		allErrs = append(allErrs, field.Forbidden(canaryPath, "can only be used with the canary strategy"))
+	}
+
+	for i, step := range rollout.Canary.Steps {
+		if step < 1 || step > 99 {
+			allErrs = append(allErrs, field.Invalid(canaryPath.Child("steps").Index(i), step, "must be between 1 and 99, the canary is promoted after the last step"))
+		} else if i > 0 && step <= rollout.Canary.Steps[i-1] {
+			allErrs = append(allErrs, field.Invalid(canaryPath.Child("steps").Index(i), step, "must be greater than the previous step"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateRollingUpdate(spec springv1alpha1.SpringBootApplicationSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	rollout := spec.Rollout
+
+	defaults, err := springv1alpha1.DefaultRollout(spec.Type)
+
+	if err != nil {
+		// The type is checked by the CRD
+		return allErrs
+	}
+
+	maxSurge, maxUnavailable := defaults.MaxSurge, defaults.MaxUnavailable
+
+	if rollout.MaxSurge != nil {
+		maxSurge = rollout.MaxSurge
+	}
+
+	if rollout.MaxUnavailable != nil {
+		maxUnavailable = rollout.MaxUnavailable
+	}
+
+	surge, surgeErr := intstr.GetScaledValueFromIntOrPercent(maxSurge, 100, true)
+
+	if surgeErr != nil || surge < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("maxSurge"), maxSurge.String(), "must be a positive number or percentage"))
+	}
+
+	unavailable, unavailableErr := intstr.GetScaledValueFromIntOrPercent(maxUnavailable, 100, false)
+
+	if unavailableErr != nil || unavailable < 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("maxUnavailable"), maxUnavailable.String(), "must be a positive number or percentage"))
+	}
+
+	if surgeErr == nil && unavailableErr == nil && surge == 0 && unavailable == 0 {
+		allErrs = append(allErrs, field.Invalid(path.Child("maxSurge"), maxSurge.String(), "can't be 0 while maxUnavailable is 0, the rollout could never make progress"))
+	}
+
+	// When only minReadySeconds is set the controller pushes the default deadline past it, so a deadline
+	// only has to be checked when one is given
+	if rollout.ProgressDeadlineSeconds != nil {
+		minReadySeconds := *defaults.MinReadySeconds
+
+		if rollout.MinReadySeconds != nil {
+			minReadySeconds = *rollout.MinReadySeconds
+		}
+
+		if *rollout.ProgressDeadlineSeconds <= minReadySeconds {
+			allErrs = append(allErrs, field.Invalid(path.Child("progressDeadlineSeconds"), *rollout.ProgressDeadlineSeconds, "must be greater than minReadySeconds"))
+		}
+	}
+
+	return allErrs
+}
+
+func validateProbes(spec springv1alpha1.SpringBootApplicationSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if spec.Probes == nil {
+		return allErrs
+	}
+
+	probes := spec.Probes
+
+	allErrs = append(allErrs, validateProbe(probes.Liveness, false, path.Child("liveness"))...)
+	allErrs = append(allErrs, validateProbe(probes.Readiness, true, path.Child("readiness"))...)
+	allErrs = append(allErrs, validateProbe(probes.Startup, false, path.Child("startup"))...)
+
+	defaults, err := springv1alpha1.DefaultProbes(spec.Type)
+	startup := probes.Startup
+
+	// The controller makes the failure threshold long enough when it isn't set, and the type is checked by the CRD
+	if err != nil || startup == nil || startup.FailureThreshold == nil {
+		return allErrs
+	}
+
+	budget := ptr.Deref(startup.InitialDelaySeconds, 0) + ptr.Deref(startup.PeriodSeconds, *defaults.Startup.PeriodSeconds)*(*startup.FailureThreshold)
+	expected := ptr.Deref(probes.ExpectedStartupSeconds, *defaults.ExpectedStartupSeconds)
+
+	if budget <= expected {
+		allErrs = append(allErrs, field.Invalid(path.Child("startup"), budget, fmt.Sprintf("allows %ds to start, which must be more than the expected %ds", budget, expected)))
+	}
+
+	return allErrs
+}
+
+func validateProbe(probe *springv1alpha1.ProbeConfig, allowSuccessThreshold bool, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if probe == nil {
+		return allErrs
+	}
+
+	probeType := probe.Type
+
+	if probeType == "" {
+		probeType = springv1alpha1.ProbeHTTP
+	}
+
+	if probeType == springv1alpha1.ProbeExec && len(probe.Command) == 0 {
+		allErrs = append(allErrs, field.Required(path.Child("command"), "must be set for exec probes"))
+	}
+
+	if probeType != springv1alpha1.ProbeExec && len(probe.Command) > 0 {
+		allErrs = append(allErrs, field.Forbidden(path.Child("command"), "can only be used with exec probes"))
+	}
+
+	if probeType == springv1alpha1.ProbeExec && probe.Port != 0 {
+		allErrs = append(allErrs, field.Forbidden(path.Child("port"), "can't be used with exec probes"))
+	}
+
+	if probeType != springv1alpha1.ProbeHTTP && probe.Path != "" {
+		allErrs = append(allErrs, field.Forbidden(path.Child("path"), "can only be used with http probes"))
+	}
+
+	if probeType != springv1alpha1.ProbeGRPC && probe.Service != "" {
+		allErrs = append(allErrs, field.Forbidden(path.Child("service"), "can only be used with grpc probes"))
+	}
+
+	if !allowSuccessThreshold && ptr.Deref(probe.SuccessThreshold, 1) != 1 {
+		allErrs = append(allErrs, field.Invalid(path.Child("successThreshold"), *probe.SuccessThreshold, "must be 1 for liveness and startup probes"))
+	}
+
+	return allErrs
+}
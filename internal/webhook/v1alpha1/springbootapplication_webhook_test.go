@@ -22,6 +22,12 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	appsv1 "k8s.io/api/apps/v1"
+	scalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 
 	springv1alpha1 "github.com/dante-lor/spring-boot-operator/api/v1alpha1"
 )
@@ -31,13 +37,16 @@ var _ = Describe("SpringBootApplication Webhook", func() {
 		obj       *springv1alpha1.SpringBootApplication
 		oldObj    *springv1alpha1.SpringBootApplication
 		defaulter SpringBootApplicationResourceDefaulter
+		validator SpringBootApplicationCustomValidator
 	)
 
 	BeforeEach(func() {
 		obj = &springv1alpha1.SpringBootApplication{}
 		oldObj = &springv1alpha1.SpringBootApplication{}
 		defaulter = SpringBootApplicationResourceDefaulter{}
+		validator = SpringBootApplicationCustomValidator{}
 		Expect(defaulter).NotTo(BeNil(), "Expected defaulter to be initialized")
+		Expect(validator).NotTo(BeNil(), "Expected validator to be initialized")
 		Expect(oldObj).NotTo(BeNil(), "Expected oldObj to be initialized")
 		Expect(obj).NotTo(BeNil(), "Expected obj to be initialized")
 	})
@@ -92,4 +101,436 @@ var _ = Describe("SpringBootApplication Webhook", func() {
 		})
 	})
 
+	Context("When creating or updating SpringBootApplication under Validating Webhook", func() {
+
+		BeforeEach(func() {
+			// Values which would be defaulted by the CRD
+			obj.Spec = springv1alpha1.SpringBootApplicationSpec{
+				Image:       "test",
+				Type:        springv1alpha1.SpringWeb,
+				Port:        8080,
+				ContextPath: "/",
+				Autoscaler: springv1alpha1.AutoscalingConfig{
+					MinReplicas: 2,
+					MaxReplicas: 10,
+				},
+			}
+		})
+
+		// expectInvalid checks the object is rejected with an error on the given field
+		expectInvalid := func(field string) {
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+
+			statusErr, ok := err.(*apierrors.StatusError)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.ErrStatus.Details.Causes).To(ContainElement(HaveField("Field", field)))
+		}
+
+		It("Should reject other objects", func() {
+			_, err := validator.ValidateCreate(ctx, &appsv1.Deployment{})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should admit a valid application", func() {
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should validate updates", func() {
+			obj.Spec.Autoscaler.MinReplicas = 20
+
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should reject unparsable resource quantities", func() {
+			obj.Spec.Resources = &springv1alpha1.ResourceDefinition{
+				CPU:    "lots",
+				Memory: "1Gi",
+			}
+
+			expectInvalid("spec.resources.cpu")
+		})
+
+		It("Should reject min replicas greater than max replicas", func() {
+			obj.Spec.Autoscaler.MinReplicas = 5
+			obj.Spec.Autoscaler.MaxReplicas = 3
+
+			expectInvalid("spec.autoscaler.minReplicas")
+		})
+
+		It("Should reject resource metrics in the custom autoscaler metrics", func() {
+			obj.Spec.Autoscaler.Metrics = []scalingv2.MetricSpec{
+				{
+					Type: scalingv2.ResourceMetricSourceType,
+				},
+			}
+
+			expectInvalid("spec.autoscaler.metrics[0].type")
+		})
+
+		It("Should require triggers when using the keda engine", func() {
+			obj.Spec.Autoscaler.Engine = springv1alpha1.EngineKeda
+
+			expectInvalid("spec.autoscaler.keda")
+		})
+
+		It("Should reject invalid scaling schedules", func() {
+			obj.Spec.Autoscaler.Schedules = []springv1alpha1.ScalingSchedule{
+				{
+					Name:     "daytime",
+					Start:    "0 8 * * 1-5",
+					End:      "every evening",
+					TimeZone: "Europe/Nowhere",
+				},
+			}
+
+			expectInvalid("spec.autoscaler.schedules[0].end")
+			expectInvalid("spec.autoscaler.schedules[0].timeZone")
+		})
+
+		It("Should reject config that isn't an object", func() {
+			obj.Spec.Config = &runtime.RawExtension{Raw: []byte(`["a", "b"]`)}
+
+			expectInvalid("spec.config")
+		})
+
+		It("Should reject a server port in config which differs from spec.port", func() {
+			obj.Spec.Config = &runtime.RawExtension{Raw: []byte(`{"server": {"port": 9000}}`)}
+
+			expectInvalid("spec.config.server.port")
+		})
+
+		It("Should allow a server port in config which matches spec.port", func() {
+			obj.Spec.Config = &runtime.RawExtension{Raw: []byte(`{"server": {"port": 8080}}`)}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		for _, contextPath := range []string{"api", "/api//v1", "/api v1", "/api?x=1"} {
+			It(fmt.Sprintf("Should reject the malformed context path %q", contextPath), func() {
+				obj.Spec.ContextPath = contextPath
+
+				expectInvalid("spec.contextPath")
+			})
+		}
+
+		It("Should reject a management port which is the same as the application port", func() {
+			obj.Spec.Management = &springv1alpha1.ManagementConfig{
+				Port: 8080,
+			}
+
+			expectInvalid("spec.management.port")
+		})
+
+		It("Should reject setting both maxUnavailable and minAvailable", func() {
+			obj.Spec.Disruption = &springv1alpha1.DisruptionConfig{
+				MaxUnavailable: ptr.To(intstr.FromInt(1)),
+				MinAvailable:   ptr.To(intstr.FromString("50%")),
+			}
+
+			expectInvalid("spec.disruption.minAvailable")
+		})
+
+		It("Should require a parent ref when exposing through a gateway", func() {
+			obj.Spec.Expose = &springv1alpha1.ExposeConfig{
+				Mode: springv1alpha1.ExposeGateway,
+				Host: "app.example.com",
+			}
+
+			expectInvalid("spec.expose.parentRef")
+		})
+
+		It("Should accept additional volumes", func() {
+			obj.Spec.Volumes = []springv1alpha1.VolumeConfig{
+				{
+					Name:      "uploads",
+					MountPath: "/data/uploads",
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: "uploads",
+					},
+				},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should reject volumes without exactly one source", func() {
+			obj.Spec.Volumes = []springv1alpha1.VolumeConfig{
+				{
+					Name:      "scratch",
+					MountPath: "/scratch",
+					EmptyDir:  &corev1.EmptyDirVolumeSource{},
+					ConfigMap: &corev1.ConfigMapVolumeSource{},
+				},
+			}
+
+			expectInvalid("spec.volumes[0]")
+		})
+
+		for _, mountPath := range []string{"/", "data", "/data/../etc", "/tmp/cache", "/config"} {
+			It(fmt.Sprintf("Should reject the mount path %q", mountPath), func() {
+				obj.Spec.Volumes = []springv1alpha1.VolumeConfig{
+					{
+						Name:      "scratch",
+						MountPath: mountPath,
+						EmptyDir:  &corev1.EmptyDirVolumeSource{},
+					},
+				}
+
+				expectInvalid("spec.volumes[0].mountPath")
+			})
+		}
+
+		It("Should reject volume names used by the operator", func() {
+			obj.Spec.Volumes = []springv1alpha1.VolumeConfig{
+				{
+					Name:      "tmp",
+					MountPath: "/scratch",
+					EmptyDir:  &corev1.EmptyDirVolumeSource{},
+				},
+			}
+
+			expectInvalid("spec.volumes[0].name")
+		})
+
+		It("Should accept a heap dump path on a writable volume", func() {
+			obj.Spec.Volumes = []springv1alpha1.VolumeConfig{
+				{
+					Name:      "dumps",
+					MountPath: "/dumps",
+					EmptyDir:  &corev1.EmptyDirVolumeSource{},
+				},
+			}
+			obj.Spec.JVM = &springv1alpha1.JVMConfig{
+				HeapDumpPath: "/dumps/heap.hprof",
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should reject a heap dump path on the read only filesystem", func() {
+			obj.Spec.JVM = &springv1alpha1.JVMConfig{
+				HeapDumpPath: "/app/heap.hprof",
+			}
+
+			expectInvalid("spec.jvm.heapDumpPath")
+		})
+
+		It("Should reject JVM settings for native images", func() {
+			obj.Spec.Type = springv1alpha1.SpringNative
+			obj.Spec.JVM = &springv1alpha1.JVMConfig{
+				HeapPercentage: ptr.To(int32(80)),
+			}
+
+			expectInvalid("spec.jvm")
+		})
+
+		It("Should reject a webflux base path which differs from the context path", func() {
+			obj.Spec.Type = springv1alpha1.SpringWebflux
+			obj.Spec.Config = &runtime.RawExtension{Raw: []byte(`{"spring":{"webflux":{"basePath":"/api"}}}`)}
+
+			expectInvalid("spec.config.spring.webflux.base-path")
+		})
+
+		It("Should reject moving actuator in the config", func() {
+			obj.Spec.ConfigProperties = "management.endpoints.web.base-path=/manage"
+
+			expectInvalid("spec.configProperties.management.endpoints.web.base-path")
+		})
+
+		It("Should accept config in the properties format", func() {
+			obj.Spec.ConfigProperties = "spring.application.name=orders\napp.servers[0].host=a\n"
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should reject properties which can't be converted to yaml", func() {
+			obj.Spec.ConfigProperties = "app=1\napp.name=orders\n"
+
+			expectInvalid("spec.configProperties")
+		})
+
+		It("Should reject setting both config formats", func() {
+			obj.Spec.Config = &runtime.RawExtension{Raw: []byte(`{"app":{"name":"orders"}}`)}
+			obj.Spec.ConfigProperties = "app.name=orders"
+
+			expectInvalid("spec.configProperties")
+		})
+
+		It("Should reject a profile config which changes the port", func() {
+			obj.Spec.ProfileConfigs = map[string]runtime.RawExtension{
+				"prod": {Raw: []byte(`{"server":{"port":9999}}`)},
+			}
+
+			expectInvalid("spec.profileConfigs[prod].server.port")
+		})
+
+		It("Should reject setting the active profiles in env when profiles are set", func() {
+			obj.Spec.Profiles = []string{"prod"}
+			obj.Spec.Env = []corev1.EnvVar{
+				{
+					Name:  "SPRING_PROFILES_ACTIVE",
+					Value: "dev",
+				},
+			}
+
+			expectInvalid("spec.env[0].name")
+		})
+
+		It("Should reject a secret listed more than once", func() {
+			obj.Spec.Secrets = []springv1alpha1.SecretConfigSource{
+				{
+					Name: "datasource",
+				},
+				{
+					Name: "datasource",
+					Key:  "datasource.yaml",
+				},
+			}
+
+			expectInvalid("spec.secrets[1].name")
+		})
+
+		It("Should reject config sources without exactly one of configMap or secret", func() {
+			obj.Spec.ConfigFrom = []springv1alpha1.ConfigFromSource{
+				{
+					ConfigMap: "shared-logging",
+				},
+				{
+					ConfigMap: "shared-datasource",
+					Secret:    "shared-datasource",
+				},
+			}
+
+			expectInvalid("spec.configFrom[1]")
+		})
+
+		It("Should reject operator managed environment variables", func() {
+			obj.Spec.Env = []corev1.EnvVar{
+				{
+					Name:  "JAVA_TOOL_OPTIONS",
+					Value: "-Xmx1g",
+				},
+			}
+
+			expectInvalid("spec.env[0].name")
+		})
+
+		It("Should reject canary steps which don't increase", func() {
+			obj.Spec.Rollout = &springv1alpha1.RolloutConfig{
+				Strategy: springv1alpha1.Canary,
+				Canary: &springv1alpha1.CanaryConfig{
+					Steps: []int32{25, 25, 100},
+				},
+			}
+
+			expectInvalid("spec.rollout.canary.steps[1]")
+			expectInvalid("spec.rollout.canary.steps[2]")
+		})
+
+		It("Should reject canary settings with the rolling update strategy", func() {
+			obj.Spec.Rollout = &springv1alpha1.RolloutConfig{
+				Strategy: springv1alpha1.RollingUpdate,
+				Canary:   &springv1alpha1.CanaryConfig{},
+			}
+
+			expectInvalid("spec.rollout.canary")
+		})
+
+		It("Should reject blue/green settings with another strategy", func() {
+			obj.Spec.Rollout = &springv1alpha1.RolloutConfig{
+				Strategy: springv1alpha1.Canary,
+				BlueGreen: &springv1alpha1.BlueGreenConfig{
+					RequireApproval: true,
+				},
+			}
+
+			expectInvalid("spec.rollout.blueGreen")
+		})
+
+		It("Should reject rolling update settings which can't make progress", func() {
+			obj.Spec.Rollout = &springv1alpha1.RolloutConfig{
+				Strategy:                springv1alpha1.RollingUpdate,
+				MaxSurge:                ptr.To(intstr.FromString("0%")),
+				MinReadySeconds:         ptr.To[int32](60),
+				ProgressDeadlineSeconds: ptr.To[int32](30),
+			}
+
+			expectInvalid("spec.rollout.maxSurge")
+			expectInvalid("spec.rollout.progressDeadlineSeconds")
+		})
+
+		It("Should reject a progress deadline within the default minReadySeconds", func() {
+			obj.Spec.Rollout = &springv1alpha1.RolloutConfig{
+				Strategy:                springv1alpha1.RollingUpdate,
+				ProgressDeadlineSeconds: ptr.To[int32](5),
+			}
+
+			expectInvalid("spec.rollout.progressDeadlineSeconds")
+		})
+
+		It("Should accept a minReadySeconds beyond the default progress deadline", func() {
+			obj.Spec.Rollout = &springv1alpha1.RolloutConfig{
+				Strategy:        springv1alpha1.RollingUpdate,
+				MinReadySeconds: ptr.To[int32](900),
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should accept no surge when replicas can be taken down first", func() {
+			obj.Spec.Rollout = &springv1alpha1.RolloutConfig{
+				Strategy:       springv1alpha1.RollingUpdate,
+				MaxSurge:       ptr.To(intstr.FromInt(0)),
+				MaxUnavailable: ptr.To(intstr.FromInt(1)),
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should reject a startup probe which doesn't allow long enough to start", func() {
+			obj.Spec.Probes = &springv1alpha1.ProbesConfig{
+				Startup: &springv1alpha1.ProbeConfig{
+					PeriodSeconds:    ptr.To[int32](2),
+					FailureThreshold: ptr.To[int32](10),
+				},
+			}
+
+			expectInvalid("spec.probes.startup")
+		})
+
+		It("Should accept a short startup probe when the application starts quickly", func() {
+			obj.Spec.Probes = &springv1alpha1.ProbesConfig{
+				ExpectedStartupSeconds: ptr.To[int32](10),
+				Startup: &springv1alpha1.ProbeConfig{
+					PeriodSeconds:    ptr.To[int32](2),
+					FailureThreshold: ptr.To[int32](10),
+				},
+			}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should require a command for exec probes", func() {
+			obj.Spec.Probes = &springv1alpha1.ProbesConfig{
+				Liveness: &springv1alpha1.ProbeConfig{
+					Type: springv1alpha1.ProbeExec,
+					Path: "/health",
+				},
+			}
+
+			expectInvalid("spec.probes.liveness.command")
+			expectInvalid("spec.probes.liveness.path")
+		})
+	})
+
 })